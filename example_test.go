@@ -0,0 +1,40 @@
+package marzban_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	marzban "github.com/VQIVS/marzban-sdk"
+	"github.com/VQIVS/marzban-sdk/models"
+)
+
+func Example() {
+	// A stand-in panel that already has a user named "alice".
+	panel := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"detail":"User already exists"}`))
+	}))
+	defer panel.Close()
+
+	client := marzban.NewClient(panel.URL, marzban.WithTimeout(10*time.Second))
+
+	_, err := client.CreateUser(context.Background(), models.UserCreate{
+		Username:  "alice",
+		Proxies:   map[string]models.ProxySettings{"vless": {}},
+		Inbounds:  map[string][]string{"vless": {"VLESS TCP REALITY"}},
+		DataLimit: 50 << 30,
+	})
+
+	var apiErr *marzban.APIError
+	if errors.As(err, &apiErr) {
+		fmt.Println(apiErr.StatusCode, apiErr.Detail)
+	}
+	fmt.Println(errors.Is(err, marzban.ErrConflict))
+	// Output:
+	// 409 User already exists
+	// true
+}
@@ -1,84 +1,114 @@
 package handlers
 
 import (
-	"bytes"
-	"encoding/json"
-	"io"
+	"context"
 	"net/http"
 
 	"github.com/VQIVS/marzban-sdk/internal/client"
-	"github.com/VQIVS/marzban-sdk/internal/models"
+	"github.com/VQIVS/marzban-sdk/models"
 )
 
-func (mc *MarzbanClient) LoginWithUsername(req models.UserLoginReq) (*models.UserLoginResponse, error) {
-	var reqBody models.UserLoginReq
-	reqBody.Username = req.Username
-	reqBody.Password = req.Password
-
-	reqBodyBytes, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := mc.Client.HttpClient.Post(mc.Client.BaseURL+client.EndpointAdminToken, "application/json", bytes.NewBuffer(reqBodyBytes))
-	if err != nil {
-		return nil, err
+// LoginWithUsername obtains an access token using the admin username and
+// password. ClientID and ClientSecret default to the ones configured with
+// client.WithClientID.
+func (mc *MarzbanClient) LoginWithUsername(ctx context.Context, req models.UserLoginReq) (*models.UserLoginResponse, error) {
+	if req.ClientID == "" && req.ClientSecret == "" {
+		req.ClientID = mc.Client.ClientID
+		req.ClientSecret = mc.Client.ClientSecret
 	}
+	return mc.Client.Login(ctx, req)
+}
 
-	defer resp.Body.Close()
+// LoginWithClientID obtains an access token using a client ID and secret in
+// place of the admin username and password.
+func (mc *MarzbanClient) LoginWithClientID(ctx context.Context, clientID, clientSecret string) (*models.UserLoginResponse, error) {
+	return mc.Client.Login(ctx, models.UserLoginReq{
+		Username:     clientID,
+		Password:     clientSecret,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+	})
+}
 
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
+// GetCurrentAdmin returns the admin the client is authenticated as.
+func (mc *MarzbanClient) GetCurrentAdmin(ctx context.Context) (*models.AdminResponse, error) {
+	return client.Execute[*models.AdminResponse](ctx, mc.Client, client.Request{
+		Method: http.MethodGet,
+		Path:   client.EndpointAdmin,
+	})
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, &models.ErrorResponse{
-			Message: "HTTP " + resp.Status,
-			Detail:  "Failed to login, status code: " + resp.Status + ", body: " + string(responseBody),
-		}
-	}
-	var loginResponse models.UserLoginResponse
-	if err := json.Unmarshal(responseBody, &loginResponse); err != nil {
-		return nil, err
-	}
-	mc.Client.Token = loginResponse.Token
-	return &loginResponse, nil
+// CreateAdmin creates a new admin. Requires a sudo admin.
+func (mc *MarzbanClient) CreateAdmin(ctx context.Context, admin models.Admin) (*models.AdminResponse, error) {
+	return client.Execute[*models.AdminResponse](ctx, mc.Client, client.Request{
+		Method: http.MethodPost,
+		Path:   client.EndpointAdmin,
+		Body:   admin,
+	})
+}
 
+// ModifyAdmin updates the admin with the given username. An empty password
+// leaves the current one unchanged.
+func (mc *MarzbanClient) ModifyAdmin(ctx context.Context, username string, admin models.Admin) (*models.AdminResponse, error) {
+	return client.Execute[*models.AdminResponse](ctx, mc.Client, client.Request{
+		Method:     http.MethodPut,
+		Path:       client.EndpointAdminByUsername,
+		PathParams: map[string]string{"username": username},
+		Body:       admin,
+	})
 }
 
-func (mc *MarzbanClient) LoginWithClientID(clientID, clientSecret string) (*models.UserLoginResponse, error) {
-	var reqBody models.UserLoginReq
-	reqBody.Username = clientID
-	reqBody.Password = clientSecret
+// RemoveAdmin deletes the admin with the given username.
+func (mc *MarzbanClient) RemoveAdmin(ctx context.Context, username string) error {
+	return mc.Client.Execute(ctx, client.Request{
+		Method:     http.MethodDelete,
+		Path:       client.EndpointAdminByUsername,
+		PathParams: map[string]string{"username": username},
+	}, nil)
+}
 
-	reqBodyBytes, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, err
-	}
+// ListAdmins returns the admins matching params.
+func (mc *MarzbanClient) ListAdmins(ctx context.Context, params models.ListAdminsParams) ([]models.AdminResponse, error) {
+	return client.Execute[[]models.AdminResponse](ctx, mc.Client, client.Request{
+		Method: http.MethodGet,
+		Path:   client.EndpointAdmins,
+		Query:  params.Query(),
+	})
+}
 
-	resp, err := mc.Client.HttpClient.Post(mc.Client.BaseURL+client.EndpointAdminToken, "application/json", bytes.NewBuffer(reqBodyBytes))
-	if err != nil {
-		return nil, err
-	}
+// DisableAdminUsers disables every user owned by the given admin.
+func (mc *MarzbanClient) DisableAdminUsers(ctx context.Context, username string) error {
+	return mc.Client.Execute(ctx, client.Request{
+		Method:     http.MethodPost,
+		Path:       client.EndpointAdminUsersDisable,
+		PathParams: map[string]string{"username": username},
+	}, nil)
+}
 
-	defer resp.Body.Close()
+// ActivateAdminUsers re-activates every user owned by the given admin.
+func (mc *MarzbanClient) ActivateAdminUsers(ctx context.Context, username string) error {
+	return mc.Client.Execute(ctx, client.Request{
+		Method:     http.MethodPost,
+		Path:       client.EndpointAdminUsersActivate,
+		PathParams: map[string]string{"username": username},
+	}, nil)
+}
 
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
+// GetAdminUsage returns the traffic used by all users of the given admin
+// since its usage was last reset.
+func (mc *MarzbanClient) GetAdminUsage(ctx context.Context, username string) (models.ByteCount, error) {
+	return client.Execute[models.ByteCount](ctx, mc.Client, client.Request{
+		Method:     http.MethodGet,
+		Path:       client.EndpointAdminUsage,
+		PathParams: map[string]string{"username": username},
+	})
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, &models.ErrorResponse{
-			Message: "HTTP " + resp.Status,
-			Detail:  "Failed to login, status code: " + resp.Status + ", body: " + string(responseBody),
-		}
-	}
-	var loginResponse models.UserLoginResponse
-	if err := json.Unmarshal(responseBody, &loginResponse); err != nil {
-		return nil, err
-	}
-	mc.Client.Token = loginResponse.Token
-	return &loginResponse, nil
+// ResetAdminUsage zeroes the users_usage counter of the given admin.
+func (mc *MarzbanClient) ResetAdminUsage(ctx context.Context, username string) (*models.AdminResponse, error) {
+	return client.Execute[*models.AdminResponse](ctx, mc.Client, client.Request{
+		Method:     http.MethodPost,
+		Path:       client.EndpointAdminUsageReset,
+		PathParams: map[string]string{"username": username},
+	})
 }
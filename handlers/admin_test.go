@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/VQIVS/marzban-sdk/internal/client"
+	"github.com/VQIVS/marzban-sdk/models"
+)
+
+func TestLoginSendsPasswordGrantForm(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		options []client.ClientOption
+		login   func(*MarzbanClient) (*models.UserLoginResponse, error)
+		want    url.Values
+	}{
+		{
+			name: "username",
+			login: func(mc *MarzbanClient) (*models.UserLoginResponse, error) {
+				return mc.LoginWithUsername(context.Background(), models.UserLoginReq{Username: "admin", Password: "p&ss=word"})
+			},
+			want: url.Values{"username": {"admin"}, "password": {"p&ss=word"}, "client_id": {""}, "client_secret": {""}},
+		},
+		{
+			name:    "username with configured client",
+			options: []client.ClientOption{client.WithClientID("cid", "csecret")},
+			login: func(mc *MarzbanClient) (*models.UserLoginResponse, error) {
+				return mc.LoginWithUsername(context.Background(), models.UserLoginReq{Username: "admin", Password: "secret"})
+			},
+			want: url.Values{"username": {"admin"}, "password": {"secret"}, "client_id": {"cid"}, "client_secret": {"csecret"}},
+		},
+		{
+			name: "client ID",
+			login: func(mc *MarzbanClient) (*models.UserLoginResponse, error) {
+				return mc.LoginWithClientID(context.Background(), "cid", "csecret")
+			},
+			want: url.Values{"username": {"cid"}, "password": {"csecret"}, "client_id": {"cid"}, "client_secret": {"csecret"}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var form url.Values
+			var contentType string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/api/admin/token" {
+					t.Errorf("request = %s %s, want POST /api/admin/token", r.Method, r.URL.Path)
+				}
+				contentType = r.Header.Get("Content-Type")
+				if err := r.ParseForm(); err != nil {
+					t.Errorf("parse form: %v", err)
+				}
+				form = r.PostForm
+				w.Write([]byte(`{"access_token":"issued-token","token_type":"bearer"}`))
+			}))
+			defer srv.Close()
+			mc := NewMarzbanClient(srv.URL, tc.options...)
+
+			resp, err := tc.login(mc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if contentType != "application/x-www-form-urlencoded" {
+				t.Errorf("Content-Type = %q, want application/x-www-form-urlencoded", contentType)
+			}
+			if got := form.Get("grant_type"); got != "password" {
+				t.Errorf("grant_type = %q, want password", got)
+			}
+			for key, want := range tc.want {
+				if got := form.Get(key); got != want[0] {
+					t.Errorf("%s = %q, want %q", key, got, want[0])
+				}
+			}
+			if resp.AccessToken != "issued-token" || mc.Client.Token() != "issued-token" {
+				t.Errorf("token = %q, stored %q, want issued-token", resp.AccessToken, mc.Client.Token())
+			}
+		})
+	}
+}
+
+func TestAdminEndpoints(t *testing.T) {
+	testEndpoints(t, []endpointCase{
+		{
+			name: "GetCurrentAdmin",
+			call: func(ctx context.Context, mc *MarzbanClient) error {
+				_, err := mc.GetCurrentAdmin(ctx)
+				return err
+			},
+			method: http.MethodGet, path: "/api/admin",
+		},
+		{
+			name: "CreateAdmin",
+			call: func(ctx context.Context, mc *MarzbanClient) error {
+				_, err := mc.CreateAdmin(ctx, models.Admin{Username: "reseller", Password: "secret", TelegramID: 42})
+				return err
+			},
+			method: http.MethodPost, path: "/api/admin",
+			body: `{"username":"reseller","password":"secret","is_sudo":false,"telegram_id":42}`,
+		},
+		{
+			name: "ModifyAdmin",
+			call: func(ctx context.Context, mc *MarzbanClient) error {
+				_, err := mc.ModifyAdmin(ctx, "re seller/1", models.Admin{Sudo: true})
+				return err
+			},
+			method: http.MethodPut, path: "/api/admin/re%20seller%2F1",
+			body: `{"is_sudo":true}`,
+		},
+		{
+			name: "RemoveAdmin",
+			call: func(ctx context.Context, mc *MarzbanClient) error {
+				return mc.RemoveAdmin(ctx, "a#b")
+			},
+			method: http.MethodDelete, path: "/api/admin/a%23b",
+		},
+		{
+			name: "ListAdmins",
+			call: func(ctx context.Context, mc *MarzbanClient) error {
+				_, err := mc.ListAdmins(ctx, models.ListAdminsParams{Offset: 20, Limit: 10, Username: "res"})
+				return err
+			},
+			method: http.MethodGet, path: "/api/admins", query: "limit=10&offset=20&username=res",
+			response: `[]`,
+		},
+		{
+			name: "ListAdmins without params",
+			call: func(ctx context.Context, mc *MarzbanClient) error {
+				_, err := mc.ListAdmins(ctx, models.ListAdminsParams{})
+				return err
+			},
+			method: http.MethodGet, path: "/api/admins",
+			response: `[]`,
+		},
+		{
+			name: "DisableAdminUsers",
+			call: func(ctx context.Context, mc *MarzbanClient) error {
+				return mc.DisableAdminUsers(ctx, "re seller")
+			},
+			method: http.MethodPost, path: "/api/admin/re%20seller/users/disable",
+		},
+		{
+			name: "ActivateAdminUsers",
+			call: func(ctx context.Context, mc *MarzbanClient) error {
+				return mc.ActivateAdminUsers(ctx, "re seller")
+			},
+			method: http.MethodPost, path: "/api/admin/re%20seller/users/activate",
+		},
+		{
+			name: "GetAdminUsage",
+			call: func(ctx context.Context, mc *MarzbanClient) error {
+				usage, err := mc.GetAdminUsage(ctx, "re/seller")
+				if err == nil && usage != 5<<30 {
+					err = fmt.Errorf("usage = %d, want %d", usage, 5<<30)
+				}
+				return err
+			},
+			method: http.MethodGet, path: "/api/admin/usage/re%2Fseller",
+			response: `5368709120`,
+		},
+		{
+			name: "ResetAdminUsage",
+			call: func(ctx context.Context, mc *MarzbanClient) error {
+				admin, err := mc.ResetAdminUsage(ctx, "re/seller")
+				if err == nil && admin.UsersUsage != 0 {
+					err = fmt.Errorf("users_usage = %d, want 0", admin.UsersUsage)
+				}
+				return err
+			},
+			method: http.MethodPost, path: "/api/admin/usage/reset/re%2Fseller",
+			response: `{"username":"re/seller","users_usage":0}`,
+		},
+	})
+}
@@ -1,9 +1,17 @@
 package handlers
 
 import (
+	"net/url"
+	"time"
+
 	"github.com/VQIVS/marzban-sdk/internal/client"
 )
 
+// dateLayout is the ISO 8601 form Marzban parses with datetime.fromisoformat.
+// The offset is always written as "+00:00" rather than "Z", which older
+// Pythons reject, so the panel never reads the value in its local zone.
+const dateLayout = "2006-01-02T15:04:05-07:00"
+
 type MarzbanClient struct {
 	*client.Client
 }
@@ -12,3 +20,16 @@ func NewMarzbanClient(baseURL string, options ...client.ClientOption) *MarzbanCl
 	c := client.NewClient(baseURL, options...)
 	return &MarzbanClient{Client: c}
 }
+
+// dateRangeQuery encodes the start and end params of the usage endpoints in
+// UTC, omitting zero times.
+func dateRangeQuery(start, end time.Time) url.Values {
+	q := url.Values{}
+	if !start.IsZero() {
+		q.Set("start", start.UTC().Format(dateLayout))
+	}
+	if !end.IsZero() {
+		q.Set("end", end.UTC().Format(dateLayout))
+	}
+	return q
+}
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// endpointCase is one handler call and the request it must send.
+type endpointCase struct {
+	name     string
+	call     func(ctx context.Context, mc *MarzbanClient) error
+	method   string
+	path     string // escaped
+	query    string // encoded
+	body     string // JSON, empty when no body is sent
+	response string // defaults to {}
+}
+
+// testEndpoints runs every case against a panel that records the request and
+// answers with the case's response.
+func testEndpoints(t *testing.T, cases []endpointCase) {
+	t.Helper()
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var method, path, query string
+			var body []byte
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				method, path, query = r.Method, r.URL.EscapedPath(), r.URL.RawQuery
+				body, _ = io.ReadAll(r.Body)
+				response := tc.response
+				if response == "" {
+					response = "{}"
+				}
+				w.Write([]byte(response))
+			}))
+			defer srv.Close()
+
+			if err := tc.call(context.Background(), NewMarzbanClient(srv.URL)); err != nil {
+				t.Fatal(err)
+			}
+			if method != tc.method || path != tc.path || query != tc.query {
+				t.Errorf("request = %s %s?%s, want %s %s?%s", method, path, query, tc.method, tc.path, tc.query)
+			}
+			if tc.body == "" {
+				if len(body) != 0 {
+					t.Errorf("body = %s, want none", body)
+				}
+				return
+			}
+			var got, want any
+			if err := json.Unmarshal(body, &got); err != nil {
+				t.Fatalf("body %q: %v", body, err)
+			}
+			if err := json.Unmarshal([]byte(tc.body), &want); err != nil {
+				t.Fatalf("want body: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("body = %s, want %s", body, tc.body)
+			}
+		})
+	}
+}
+
+// newBlockingPanel returns a panel whose handler blocks until the client
+// gives up on the request. started receives a value once a request arrives.
+func newBlockingPanel(t *testing.T) (srv *httptest.Server, started <-chan struct{}) {
+	t.Helper()
+	ch := make(chan struct{}, 1)
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+		<-r.Context().Done()
+	}))
+	t.Cleanup(srv.Close)
+	return srv, ch
+}
+
+func TestCancelAbortsInFlightCall(t *testing.T) {
+	srv, started := newBlockingPanel(t)
+	mc := NewMarzbanClient(srv.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-started
+		cancel()
+	}()
+
+	begin := time.Now()
+	_, err := mc.GetExpiredUsers(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(begin); elapsed > 2*time.Second {
+		t.Fatalf("call returned after %v, want prompt abort", elapsed)
+	}
+}
+
+func TestDeadlineAbortsInFlightCall(t *testing.T) {
+	srv, _ := newBlockingPanel(t)
+	mc := NewMarzbanClient(srv.URL)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	begin := time.Now()
+	_, err := mc.GetUserByUsername(ctx, "alice")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(begin); elapsed > 2*time.Second {
+		t.Fatalf("call returned after %v, want prompt abort", elapsed)
+	}
+}
+
+func TestDateRangeQuery(t *testing.T) {
+	tehran := time.FixedZone("IRST", 3*3600+1800)
+	for _, tc := range []struct {
+		start, end time.Time
+		want       string
+	}{
+		{time.Time{}, time.Time{}, ""},
+		{
+			time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), time.Time{},
+			"start=2026-01-01T00%3A00%3A00%2B00%3A00",
+		},
+		{
+			time.Date(2026, 1, 1, 3, 30, 0, 0, tehran), time.Date(2026, 2, 1, 3, 30, 0, 0, tehran),
+			"end=2026-02-01T00%3A00%3A00%2B00%3A00&start=2026-01-01T00%3A00%3A00%2B00%3A00",
+		},
+	} {
+		if got := dateRangeQuery(tc.start, tc.end).Encode(); got != tc.want {
+			t.Errorf("dateRangeQuery(%v, %v) = %q, want %q", tc.start, tc.end, got, tc.want)
+		}
+	}
+}
+
+func TestUsageMethodsSendDateRange(t *testing.T) {
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Path+"?"+r.URL.RawQuery)
+		w.Write([]byte(`{"usages":[]}`))
+	}))
+	defer srv.Close()
+	mc := NewMarzbanClient(srv.URL)
+	ctx := context.Background()
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+
+	if _, err := mc.GetNodesUsage(ctx, start, end); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mc.GetUserUsage(ctx, "alice", start, end); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mc.GetUsersUsage(ctx, start, end, "reseller1", "reseller2"); err != nil {
+		t.Fatal(err)
+	}
+
+	const dates = "end=2026-04-01T00%3A00%3A00%2B00%3A00&start=2026-03-01T00%3A00%3A00%2B00%3A00"
+	want := []string{
+		"/api/nodes/usage?" + dates,
+		"/api/user/alice/usage?" + dates,
+		"/api/users/usage?admin=reseller1&admin=reseller2&" + dates,
+	}
+	if len(queries) != len(want) {
+		t.Fatalf("requests = %q, want %q", queries, want)
+	}
+	for i := range want {
+		if queries[i] != want[i] {
+			t.Errorf("request %d = %q, want %q", i, queries[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/VQIVS/marzban-sdk/internal/client"
+	"github.com/VQIVS/marzban-sdk/models"
+)
+
+// GetCoreStats returns the Xray core version, whether it is running and the
+// websocket path its logs are streamed on.
+func (mc *MarzbanClient) GetCoreStats(ctx context.Context) (*models.CoreStats, error) {
+	return client.Execute[*models.CoreStats](ctx, mc.Client, client.Request{
+		Method: http.MethodGet,
+		Path:   client.EndpointCore,
+	})
+}
+
+// RestartCore restarts the Xray core on the panel and all connected nodes.
+func (mc *MarzbanClient) RestartCore(ctx context.Context) error {
+	return mc.Client.Execute(ctx, client.Request{
+		Method: http.MethodPost,
+		Path:   client.EndpointCoreRestart,
+	}, nil)
+}
+
+// GetCoreConfig returns the Xray JSON config the core runs with.
+func (mc *MarzbanClient) GetCoreConfig(ctx context.Context) (*models.XrayConfig, error) {
+	return client.Execute[*models.XrayConfig](ctx, mc.Client, client.Request{
+		Method: http.MethodGet,
+		Path:   client.EndpointCoreConfig,
+	})
+}
+
+// ModifyCoreConfig replaces the Xray config and restarts the core with it.
+func (mc *MarzbanClient) ModifyCoreConfig(ctx context.Context, config *models.XrayConfig) (*models.XrayConfig, error) {
+	return client.Execute[*models.XrayConfig](ctx, mc.Client, client.Request{
+		Method: http.MethodPut,
+		Path:   client.EndpointCoreConfig,
+		Body:   config,
+	})
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/VQIVS/marzban-sdk/models"
+)
+
+func TestCoreEndpoints(t *testing.T) {
+	testEndpoints(t, []endpointCase{
+		{
+			name: "GetCoreStats",
+			call: func(ctx context.Context, mc *MarzbanClient) error {
+				_, err := mc.GetCoreStats(ctx)
+				return err
+			},
+			method: http.MethodGet, path: "/api/core",
+			response: `{"version":"1.8.4","started":true,"logs_websocket":"/api/core/logs"}`,
+		},
+		{
+			name: "RestartCore",
+			call: func(ctx context.Context, mc *MarzbanClient) error {
+				return mc.RestartCore(ctx)
+			},
+			method: http.MethodPost, path: "/api/core/restart",
+		},
+		{
+			name: "GetCoreConfig",
+			call: func(ctx context.Context, mc *MarzbanClient) error {
+				_, err := mc.GetCoreConfig(ctx)
+				return err
+			},
+			method: http.MethodGet, path: "/api/core/config",
+			response: `{"log":{"loglevel":"warning"},"inbounds":[]}`,
+		},
+		{
+			name: "ModifyCoreConfig",
+			call: func(ctx context.Context, mc *MarzbanClient) error {
+				var config models.XrayConfig
+				if err := json.Unmarshal([]byte(`{"log":{"loglevel":"info"},"custom":{"kept":true}}`), &config); err != nil {
+					return err
+				}
+				_, err := mc.ModifyCoreConfig(ctx, &config)
+				return err
+			},
+			method: http.MethodPut, path: "/api/core/config",
+			body: `{"log":{"loglevel":"info"},"custom":{"kept":true}}`,
+		},
+	})
+}
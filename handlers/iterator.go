@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/VQIVS/marzban-sdk/models"
+)
+
+// defaultPageSize is the number of users UserIterator requests at a time
+// when UserIteratorOptions.PageSize is not set.
+const defaultPageSize = 100
+
+// defaultIteratorSort orders the pages of a UserIterator when the params do
+// not. Marzban applies no ORDER BY without a sort, so offset paging could
+// otherwise skip or repeat users.
+const defaultIteratorSort = "created_at"
+
+// UserIteratorOptions configures IterateUsers.
+type UserIteratorOptions struct {
+	PageSize int  // users per request, defaults to 100
+	Prefetch bool // request the next page while the current one is consumed
+}
+
+// UserIterator streams the users matching a filter page by page. Use it as
+//
+//	it := mc.IterateUsers(ctx, params, opts)
+//	for it.Next() {
+//		user := it.User()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+// A UserIterator is not safe for concurrent use.
+type UserIterator struct {
+	mc       *MarzbanClient
+	ctx      context.Context
+	params   models.ListUsersParams
+	pageSize int
+	prefetch bool
+
+	offset    int // offset of the next page to request
+	remaining int // users left to request, or -1 for no limit
+	pending   <-chan userPage
+	page      []models.UserResponse
+	index     int
+	total     int
+	user      *models.UserResponse
+	done      bool
+	err       error
+}
+
+type userPage struct {
+	users []models.UserResponse
+	total int
+	limit int
+	err   error
+}
+
+// IterateUsers returns an iterator over the users matching params. Offset
+// and Limit of params bound the whole iteration rather than a single page.
+// Users are sorted by creation time unless params.Sort says otherwise.
+func (mc *MarzbanClient) IterateUsers(ctx context.Context, params models.ListUsersParams, opts UserIteratorOptions) *UserIterator {
+	it := &UserIterator{
+		mc:        mc,
+		ctx:       ctx,
+		params:    params,
+		pageSize:  opts.PageSize,
+		prefetch:  opts.Prefetch,
+		offset:    params.Offset,
+		remaining: -1,
+	}
+	if it.pageSize <= 0 {
+		it.pageSize = defaultPageSize
+	}
+	if params.Limit > 0 {
+		it.remaining = params.Limit
+	}
+	if it.params.Sort == "" {
+		it.params.Sort = defaultIteratorSort
+	}
+	return it
+}
+
+// Next advances to the next user, fetching a new page when needed. It
+// returns false when the users are exhausted, the context is done or a
+// request failed; Err tells these apart.
+func (it *UserIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+		it.user = nil
+		return false
+	}
+	for it.index >= len(it.page) {
+		if it.done {
+			it.user = nil
+			return false
+		}
+		if err := it.loadPage(); err != nil {
+			it.err = err
+			it.user = nil
+			return false
+		}
+	}
+	it.user = &it.page[it.index]
+	it.index++
+	return true
+}
+
+// User returns the current user. It is only valid after Next returned true.
+func (it *UserIterator) User() *models.UserResponse {
+	return it.user
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *UserIterator) Err() error {
+	return it.err
+}
+
+// Total returns the number of users matching the filter as reported with
+// the last page fetched.
+func (it *UserIterator) Total() int {
+	return it.total
+}
+
+func (it *UserIterator) loadPage() error {
+	pending := it.pending
+	it.pending = nil
+	if pending == nil {
+		offset, limit, ok := it.nextRequest()
+		if !ok {
+			it.done = true
+			return nil
+		}
+		pending = it.fetch(offset, limit)
+	}
+
+	var page userPage
+	select {
+	case page = <-pending:
+	case <-it.ctx.Done():
+		return it.ctx.Err()
+	}
+	if page.err != nil {
+		return page.err
+	}
+	it.page, it.index, it.total = page.users, 0, page.total
+
+	if len(page.users) < page.limit || it.offset >= page.total {
+		it.done = true
+		return nil
+	}
+	if it.prefetch {
+		if offset, limit, ok := it.nextRequest(); ok {
+			it.pending = it.fetch(offset, limit)
+		}
+	}
+	return nil
+}
+
+// nextRequest reserves the offset and limit of the next page.
+func (it *UserIterator) nextRequest() (offset, limit int, ok bool) {
+	limit = it.pageSize
+	if it.remaining >= 0 {
+		if it.remaining == 0 {
+			return 0, 0, false
+		}
+		limit = min(limit, it.remaining)
+		it.remaining -= limit
+	}
+	offset = it.offset
+	it.offset += limit
+	return offset, limit, true
+}
+
+// fetch requests one page in the background.
+func (it *UserIterator) fetch(offset, limit int) <-chan userPage {
+	ch := make(chan userPage, 1)
+	params := it.params
+	params.Offset, params.Limit = offset, limit
+	go func() {
+		response, err := it.mc.ListUsers(it.ctx, params)
+		if err != nil {
+			ch <- userPage{err: err}
+			return
+		}
+		ch <- userPage{users: response.Users, total: response.Total, limit: limit}
+	}()
+	return ch
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/VQIVS/marzban-sdk/models"
+)
+
+// usersPanel serves /api/users from a list of users named user000, user001,
+// ... and records the query of every request.
+type usersPanel struct {
+	mu      sync.Mutex
+	queries []string
+	total   int
+}
+
+func newUsersPanel(t *testing.T, total int) (*usersPanel, *MarzbanClient) {
+	t.Helper()
+	p := &usersPanel{total: total}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		p.mu.Lock()
+		p.queries = append(p.queries, r.URL.RawQuery)
+		p.mu.Unlock()
+
+		offset, _ := strconv.Atoi(q.Get("offset"))
+		limit, _ := strconv.Atoi(q.Get("limit"))
+		response := models.UsersResponse{Users: []models.UserResponse{}, Total: p.total}
+		for i := offset; i < min(offset+limit, p.total); i++ {
+			response.Users = append(response.Users, models.UserResponse{Username: fmt.Sprintf("user%03d", i)})
+		}
+		json.NewEncoder(w).Encode(response)
+	}))
+	t.Cleanup(srv.Close)
+	return p, NewMarzbanClient(srv.URL)
+}
+
+func (p *usersPanel) requests() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.queries...)
+}
+
+// collect drains it and returns the usernames it yielded.
+func collect(t *testing.T, it *UserIterator) []string {
+	t.Helper()
+	var names []string
+	for it.Next() {
+		names = append(names, it.User().Username)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+	return names
+}
+
+func checkUsers(t *testing.T, names []string, from, count int) {
+	t.Helper()
+	if len(names) != count {
+		t.Fatalf("got %d users, want %d", len(names), count)
+	}
+	for i, name := range names {
+		if want := fmt.Sprintf("user%03d", from+i); name != want {
+			t.Fatalf("user %d = %q, want %q", i, name, want)
+		}
+	}
+}
+
+func checkRequests(t *testing.T, got, want []string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("requests = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("request %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestIterateUsersPages(t *testing.T) {
+	panel, mc := newUsersPanel(t, 250)
+	it := mc.IterateUsers(context.Background(), models.ListUsersParams{Status: models.UserStatusActive}, UserIteratorOptions{PageSize: 100})
+
+	checkUsers(t, collect(t, it), 0, 250)
+	if it.Total() != 250 {
+		t.Errorf("Total() = %d, want 250", it.Total())
+	}
+	checkRequests(t, panel.requests(), []string{
+		"limit=100&sort=created_at&status=active",
+		"limit=100&offset=100&sort=created_at&status=active",
+		"limit=100&offset=200&sort=created_at&status=active",
+	})
+}
+
+func TestIterateUsersStopsOnFullLastPage(t *testing.T) {
+	panel, mc := newUsersPanel(t, 200)
+	it := mc.IterateUsers(context.Background(), models.ListUsersParams{Sort: "-username"}, UserIteratorOptions{PageSize: 100})
+
+	checkUsers(t, collect(t, it), 0, 200)
+	// The total tells the iterator the second page was the last one.
+	checkRequests(t, panel.requests(), []string{
+		"limit=100&sort=-username",
+		"limit=100&offset=100&sort=-username",
+	})
+}
+
+func TestIterateUsersEmpty(t *testing.T) {
+	panel, mc := newUsersPanel(t, 0)
+	it := mc.IterateUsers(context.Background(), models.ListUsersParams{}, UserIteratorOptions{})
+
+	if names := collect(t, it); len(names) != 0 {
+		t.Fatalf("users = %q, want none", names)
+	}
+	checkRequests(t, panel.requests(), []string{"limit=100&sort=created_at"})
+}
+
+func TestIterateUsersBoundedLimit(t *testing.T) {
+	panel, mc := newUsersPanel(t, 500)
+	it := mc.IterateUsers(context.Background(), models.ListUsersParams{Offset: 10, Limit: 130}, UserIteratorOptions{PageSize: 50})
+
+	checkUsers(t, collect(t, it), 10, 130)
+	checkRequests(t, panel.requests(), []string{
+		"limit=50&offset=10&sort=created_at",
+		"limit=50&offset=60&sort=created_at",
+		"limit=30&offset=110&sort=created_at",
+	})
+}
+
+func TestIterateUsersPrefetch(t *testing.T) {
+	panel, mc := newUsersPanel(t, 120)
+	it := mc.IterateUsers(context.Background(), models.ListUsersParams{}, UserIteratorOptions{PageSize: 50, Prefetch: true})
+
+	if !it.Next() {
+		t.Fatalf("Next() = false, err %v", it.Err())
+	}
+	// The second page is requested while the first is still being consumed.
+	deadline := time.Now().Add(2 * time.Second)
+	for len(panel.requests()) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("second page was not prefetched")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	names := append([]string{it.User().Username}, collect(t, it)...)
+	checkUsers(t, names, 0, 120)
+	checkRequests(t, panel.requests(), []string{
+		"limit=50&sort=created_at",
+		"limit=50&offset=50&sort=created_at",
+		"limit=50&offset=100&sort=created_at",
+	})
+}
+
+func TestIterateUsersCancelBetweenPages(t *testing.T) {
+	panel, mc := newUsersPanel(t, 300)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	it := mc.IterateUsers(ctx, models.ListUsersParams{}, UserIteratorOptions{PageSize: 100})
+
+	for i := 0; i < 100; i++ {
+		if !it.Next() {
+			t.Fatalf("Next() = false at user %d, err %v", i, it.Err())
+		}
+	}
+	cancel()
+	if it.Next() {
+		t.Fatalf("Next() = true after cancel, user %q", it.User().Username)
+	}
+	if !errors.Is(it.Err(), context.Canceled) {
+		t.Fatalf("Err() = %v, want context.Canceled", it.Err())
+	}
+	if it.User() != nil {
+		t.Errorf("User() = %v after the iteration stopped", it.User())
+	}
+	if got := len(panel.requests()); got != 1 {
+		t.Errorf("requests = %d, want only the first page", got)
+	}
+}
+
+func TestIterateUsersCancelWhilePrefetching(t *testing.T) {
+	_, mc := newUsersPanel(t, 300)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	it := mc.IterateUsers(ctx, models.ListUsersParams{}, UserIteratorOptions{PageSize: 100, Prefetch: true})
+
+	for i := 0; i < 50; i++ {
+		if !it.Next() {
+			t.Fatalf("Next() = false at user %d, err %v", i, it.Err())
+		}
+	}
+	cancel()
+	if it.Next() {
+		t.Fatal("Next() = true after cancel")
+	}
+	if !errors.Is(it.Err(), context.Canceled) {
+		t.Fatalf("Err() = %v, want context.Canceled", it.Err())
+	}
+}
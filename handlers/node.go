@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/VQIVS/marzban-sdk/internal/client"
+	"github.com/VQIVS/marzban-sdk/models"
+)
+
+// AddNode registers a new node with the panel.
+func (mc *MarzbanClient) AddNode(ctx context.Context, node models.NodeCreate) (*models.Node, error) {
+	return client.Execute[*models.Node](ctx, mc.Client, client.Request{
+		Method: http.MethodPost,
+		Path:   client.EndpointNode,
+		Body:   node,
+	})
+}
+
+// GetNode returns the node with the given ID.
+func (mc *MarzbanClient) GetNode(ctx context.Context, nodeID int) (*models.Node, error) {
+	return client.Execute[*models.Node](ctx, mc.Client, client.Request{
+		Method:     http.MethodGet,
+		Path:       client.EndpointNodeByID,
+		PathParams: nodeIDParam(nodeID),
+	})
+}
+
+// ModifyNode updates the node with the given ID.
+func (mc *MarzbanClient) ModifyNode(ctx context.Context, nodeID int, node models.NodeModify) (*models.Node, error) {
+	return client.Execute[*models.Node](ctx, mc.Client, client.Request{
+		Method:     http.MethodPut,
+		Path:       client.EndpointNodeByID,
+		PathParams: nodeIDParam(nodeID),
+		Body:       node,
+	})
+}
+
+// RemoveNode deletes the node with the given ID.
+func (mc *MarzbanClient) RemoveNode(ctx context.Context, nodeID int) error {
+	return mc.Client.Execute(ctx, client.Request{
+		Method:     http.MethodDelete,
+		Path:       client.EndpointNodeByID,
+		PathParams: nodeIDParam(nodeID),
+	}, nil)
+}
+
+// ListNodes returns every node registered with the panel.
+func (mc *MarzbanClient) ListNodes(ctx context.Context) ([]models.Node, error) {
+	return client.Execute[[]models.Node](ctx, mc.Client, client.Request{
+		Method: http.MethodGet,
+		Path:   client.EndpointNodes,
+	})
+}
+
+// ReconnectNode makes the panel drop and re-establish its connection to the
+// node.
+func (mc *MarzbanClient) ReconnectNode(ctx context.Context, nodeID int) error {
+	return mc.Client.Execute(ctx, client.Request{
+		Method:     http.MethodPost,
+		Path:       client.EndpointNodeReconnect,
+		PathParams: nodeIDParam(nodeID),
+	}, nil)
+}
+
+// GetNodeSettings returns the panel's client certificate, which must be
+// installed on a node before it can be added.
+func (mc *MarzbanClient) GetNodeSettings(ctx context.Context) (*models.NodeSettings, error) {
+	return client.Execute[*models.NodeSettings](ctx, mc.Client, client.Request{
+		Method: http.MethodGet,
+		Path:   client.EndpointNodeSettings,
+	})
+}
+
+func nodeIDParam(nodeID int) map[string]string {
+	return map[string]string{"node_id": strconv.Itoa(nodeID)}
+}
+
+// GetNodesUsage returns the uplink and downlink totals of every node between
+// start and end. Zero times leave the bound to the panel, which defaults to
+// the last 30 days.
+func (mc *MarzbanClient) GetNodesUsage(ctx context.Context, start, end time.Time) ([]models.NodeUsage, error) {
+	response, err := client.Execute[models.NodesUsageResponse](ctx, mc.Client, client.Request{
+		Method: http.MethodGet,
+		Path:   client.EndpointNodesUsage,
+		Query:  dateRangeQuery(start, end),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return response.Usages, nil
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/VQIVS/marzban-sdk/models"
+)
+
+func TestNodeEndpoints(t *testing.T) {
+	noHost := false
+	testEndpoints(t, []endpointCase{
+		{
+			name: "AddNode keeps the panel's host default",
+			call: func(ctx context.Context, mc *MarzbanClient) error {
+				_, err := mc.AddNode(ctx, models.NodeCreate{Name: "de-1", Address: "10.0.0.2"})
+				return err
+			},
+			method: http.MethodPost, path: "/api/node",
+			body: `{"name":"de-1","address":"10.0.0.2"}`,
+		},
+		{
+			name: "AddNode without a host",
+			call: func(ctx context.Context, mc *MarzbanClient) error {
+				_, err := mc.AddNode(ctx, models.NodeCreate{Name: "de-1", Address: "10.0.0.2", Port: 7000, AddAsNewHost: &noHost})
+				return err
+			},
+			method: http.MethodPost, path: "/api/node",
+			body: `{"name":"de-1","address":"10.0.0.2","port":7000,"add_as_new_host":false}`,
+		},
+		{
+			name: "GetNode",
+			call: func(ctx context.Context, mc *MarzbanClient) error {
+				_, err := mc.GetNode(ctx, 3)
+				return err
+			},
+			method: http.MethodGet, path: "/api/node/3",
+		},
+		{
+			name: "ModifyNode",
+			call: func(ctx context.Context, mc *MarzbanClient) error {
+				_, err := mc.ModifyNode(ctx, 3, models.NodeModify{Status: models.NodeStatusDisabled})
+				return err
+			},
+			method: http.MethodPut, path: "/api/node/3",
+			body: `{"status":"disabled"}`,
+		},
+		{
+			name: "RemoveNode",
+			call: func(ctx context.Context, mc *MarzbanClient) error {
+				return mc.RemoveNode(ctx, 3)
+			},
+			method: http.MethodDelete, path: "/api/node/3",
+		},
+		{
+			name: "ListNodes",
+			call: func(ctx context.Context, mc *MarzbanClient) error {
+				_, err := mc.ListNodes(ctx)
+				return err
+			},
+			method: http.MethodGet, path: "/api/nodes",
+			response: `[]`,
+		},
+		{
+			name: "ReconnectNode",
+			call: func(ctx context.Context, mc *MarzbanClient) error {
+				return mc.ReconnectNode(ctx, 3)
+			},
+			method: http.MethodPost, path: "/api/node/3/reconnect",
+		},
+		{
+			name: "GetNodeSettings",
+			call: func(ctx context.Context, mc *MarzbanClient) error {
+				_, err := mc.GetNodeSettings(ctx)
+				return err
+			},
+			method: http.MethodGet, path: "/api/node/settings",
+		},
+	})
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/VQIVS/marzban-sdk/internal/client"
+	"github.com/VQIVS/marzban-sdk/models"
+)
+
+// GetSystemStats returns memory, CPU, user counts and bandwidth of the panel.
+func (mc *MarzbanClient) GetSystemStats(ctx context.Context) (*models.SystemStats, error) {
+	return client.Execute[*models.SystemStats](ctx, mc.Client, client.Request{
+		Method: http.MethodGet,
+		Path:   client.EndpointSystem,
+	})
+}
+
+// GetInbounds returns the panel's inbounds keyed by protocol.
+func (mc *MarzbanClient) GetInbounds(ctx context.Context) (map[string][]models.ProxyInbound, error) {
+	return client.Execute[map[string][]models.ProxyInbound](ctx, mc.Client, client.Request{
+		Method: http.MethodGet,
+		Path:   client.EndpointInbounds,
+	})
+}
+
+// GetHosts returns the hosts of every inbound keyed by inbound tag.
+func (mc *MarzbanClient) GetHosts(ctx context.Context) (map[string][]models.ProxyHost, error) {
+	return client.Execute[map[string][]models.ProxyHost](ctx, mc.Client, client.Request{
+		Method: http.MethodGet,
+		Path:   client.EndpointHosts,
+	})
+}
+
+// ModifyHosts replaces the hosts of the inbound tags present in hosts and
+// returns the resulting hosts of every inbound.
+func (mc *MarzbanClient) ModifyHosts(ctx context.Context, hosts map[string][]models.ProxyHost) (map[string][]models.ProxyHost, error) {
+	return client.Execute[map[string][]models.ProxyHost](ctx, mc.Client, client.Request{
+		Method: http.MethodPut,
+		Path:   client.EndpointHosts,
+		Body:   hosts,
+	})
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/VQIVS/marzban-sdk/models"
+)
+
+func TestSystemEndpoints(t *testing.T) {
+	port := 443
+	testEndpoints(t, []endpointCase{
+		{
+			name: "GetSystemStats",
+			call: func(ctx context.Context, mc *MarzbanClient) error {
+				_, err := mc.GetSystemStats(ctx)
+				return err
+			},
+			method: http.MethodGet, path: "/api/system",
+		},
+		{
+			name: "GetInbounds",
+			call: func(ctx context.Context, mc *MarzbanClient) error {
+				_, err := mc.GetInbounds(ctx)
+				return err
+			},
+			method: http.MethodGet, path: "/api/inbounds",
+			response: `{"vless":[{"tag":"VLESS TCP","protocol":"vless","network":"tcp","tls":"reality","port":443}]}`,
+		},
+		{
+			name: "GetHosts",
+			call: func(ctx context.Context, mc *MarzbanClient) error {
+				_, err := mc.GetHosts(ctx)
+				return err
+			},
+			method: http.MethodGet, path: "/api/hosts",
+		},
+		{
+			name: "ModifyHosts",
+			call: func(ctx context.Context, mc *MarzbanClient) error {
+				_, err := mc.ModifyHosts(ctx, map[string][]models.ProxyHost{
+					"VLESS TCP": {{Remark: "DE {USERNAME}", Address: "de.example.com", Port: &port, Security: models.ProxyHostSecurityTLS}},
+				})
+				return err
+			},
+			method: http.MethodPut, path: "/api/hosts",
+			body: `{"VLESS TCP":[{"remark":"DE {USERNAME}","address":"de.example.com","port":443,"security":"tls"}]}`,
+		},
+	})
+}
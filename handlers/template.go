@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/VQIVS/marzban-sdk/internal/client"
+	"github.com/VQIVS/marzban-sdk/models"
+)
+
+// ListUserTemplates returns the user templates of the panel.
+func (mc *MarzbanClient) ListUserTemplates(ctx context.Context, params models.ListUserTemplatesParams) ([]models.UserTemplate, error) {
+	return client.Execute[[]models.UserTemplate](ctx, mc.Client, client.Request{
+		Method: http.MethodGet,
+		Path:   client.EndpointUserTemplate,
+		Query:  params.Query(),
+	})
+}
+
+// GetUserTemplate returns the user template with the given ID.
+func (mc *MarzbanClient) GetUserTemplate(ctx context.Context, templateID int) (*models.UserTemplate, error) {
+	return client.Execute[*models.UserTemplate](ctx, mc.Client, client.Request{
+		Method:     http.MethodGet,
+		Path:       client.EndpointUserTemplateByID,
+		PathParams: templateIDParam(templateID),
+	})
+}
+
+// CreateUserTemplate creates a user template. The ID of template is ignored.
+func (mc *MarzbanClient) CreateUserTemplate(ctx context.Context, template models.UserTemplate) (*models.UserTemplate, error) {
+	template.ID = 0
+	return client.Execute[*models.UserTemplate](ctx, mc.Client, client.Request{
+		Method: http.MethodPost,
+		Path:   client.EndpointUserTemplate,
+		Body:   template,
+	})
+}
+
+// ModifyUserTemplate replaces the user template with the given ID.
+func (mc *MarzbanClient) ModifyUserTemplate(ctx context.Context, templateID int, template models.UserTemplate) (*models.UserTemplate, error) {
+	template.ID = 0
+	return client.Execute[*models.UserTemplate](ctx, mc.Client, client.Request{
+		Method:     http.MethodPut,
+		Path:       client.EndpointUserTemplateByID,
+		PathParams: templateIDParam(templateID),
+		Body:       template,
+	})
+}
+
+// DeleteUserTemplate deletes the user template with the given ID.
+func (mc *MarzbanClient) DeleteUserTemplate(ctx context.Context, templateID int) error {
+	return mc.Client.Execute(ctx, client.Request{
+		Method:     http.MethodDelete,
+		Path:       client.EndpointUserTemplateByID,
+		PathParams: templateIDParam(templateID),
+	}, nil)
+}
+
+func templateIDParam(templateID int) map[string]string {
+	return map[string]string{"template_id": strconv.Itoa(templateID)}
+}
+
+// CreateUserFromTemplate creates a user from the plan of a user template.
+// The username gets the template's prefix and suffix, Expire is set
+// expire_duration from now, and the data limit and inbounds are taken from
+// the template, with a proxy for each of their protocols. A template without
+// inbounds gets a proxy for every protocol the panel serves. Non-zero fields of
+// overrides take precedence over the template; the username of overrides is
+// ignored.
+func (mc *MarzbanClient) CreateUserFromTemplate(ctx context.Context, templateID int, username string, overrides models.UserCreate) (*models.UserResponse, error) {
+	template, err := mc.GetUserTemplate(ctx, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	user := overrides
+	user.Username = template.UsernamePrefix + username + template.UsernameSuffix
+	if user.Expire == 0 && template.ExpireDuration > 0 {
+		user.Expire = time.Now().Unix() + template.ExpireDuration
+	}
+	if user.DataLimit == 0 {
+		user.DataLimit = template.DataLimit
+	}
+	if len(user.Inbounds) == 0 {
+		user.Inbounds = template.Inbounds
+	}
+	if len(user.Proxies) == 0 {
+		protocols := make([]string, 0, len(user.Inbounds))
+		for protocol := range user.Inbounds {
+			protocols = append(protocols, protocol)
+		}
+		if len(protocols) == 0 {
+			// A template without inbounds grants every inbound of the panel.
+			inbounds, err := mc.GetInbounds(ctx)
+			if err != nil {
+				return nil, err
+			}
+			for protocol := range inbounds {
+				protocols = append(protocols, protocol)
+			}
+		}
+		if len(protocols) == 0 {
+			return nil, fmt.Errorf("marzban: template %d and the panel have no inbounds to give user %q a proxy", templateID, user.Username)
+		}
+		// Empty settings let the panel generate the credentials.
+		user.Proxies = make(map[string]models.ProxySettings, len(protocols))
+		for _, protocol := range protocols {
+			user.Proxies[protocol] = models.ProxySettings{}
+		}
+	}
+	return mc.CreateUser(ctx, user)
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/VQIVS/marzban-sdk/models"
+)
+
+// newTemplatePanel serves template and inbounds and records the body of the
+// user creation request in created.
+func newTemplatePanel(t *testing.T, template, inbounds string, created *models.UserCreate) *MarzbanClient {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/user_template/7":
+			w.Write([]byte(template))
+		case r.Method == http.MethodGet && r.URL.Path == "/api/inbounds":
+			w.Write([]byte(inbounds))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/user":
+			if err := json.NewDecoder(r.Body).Decode(created); err != nil {
+				t.Errorf("decode user: %v", err)
+			}
+			w.Write([]byte(`{"username":"` + created.Username + `"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return NewMarzbanClient(srv.URL)
+}
+
+const planTemplate = `{
+	"id": 7,
+	"data_limit": 1073741824,
+	"expire_duration": 86400,
+	"username_prefix": "pre_",
+	"username_suffix": "_suf",
+	"inbounds": {"vless": ["VLESS TCP"], "trojan": ["Trojan WS"]}
+}`
+
+func TestCreateUserFromTemplateAppliesPlan(t *testing.T) {
+	var created models.UserCreate
+	mc := newTemplatePanel(t, planTemplate, `{}`, &created)
+
+	before := time.Now().Unix()
+	if _, err := mc.CreateUserFromTemplate(context.Background(), 7, "alice", models.UserCreate{}); err != nil {
+		t.Fatal(err)
+	}
+	after := time.Now().Unix()
+
+	if created.Username != "pre_alice_suf" {
+		t.Errorf("username = %q, want pre_alice_suf", created.Username)
+	}
+	if created.Expire < before+86400 || created.Expire > after+86400 {
+		t.Errorf("expire = %d, want %d..%d", created.Expire, before+86400, after+86400)
+	}
+	if created.DataLimit != 1<<30 {
+		t.Errorf("data_limit = %d, want %d", created.DataLimit, 1<<30)
+	}
+	if len(created.Inbounds) != 2 || created.Inbounds["vless"][0] != "VLESS TCP" {
+		t.Errorf("inbounds = %v, want the template's", created.Inbounds)
+	}
+	if _, ok := created.Proxies["vless"]; !ok || len(created.Proxies) != 2 {
+		t.Errorf("proxies = %v, want vless and trojan", created.Proxies)
+	}
+}
+
+func TestCreateUserFromTemplateOverrides(t *testing.T) {
+	var created models.UserCreate
+	mc := newTemplatePanel(t, planTemplate, `{}`, &created)
+
+	overrides := models.UserCreate{
+		Username:  "ignored",
+		Expire:    1700000000,
+		DataLimit: 5,
+		Inbounds:  map[string][]string{"vmess": {"VMess TCP"}},
+		Proxies:   map[string]models.ProxySettings{"vmess": {ID: "35e4e39c-7d5c-4f4b-8b71-558e4f37ff53"}},
+		Note:      "vip",
+	}
+	if _, err := mc.CreateUserFromTemplate(context.Background(), 7, "bob", overrides); err != nil {
+		t.Fatal(err)
+	}
+
+	if created.Username != "pre_bob_suf" {
+		t.Errorf("username = %q, want pre_bob_suf", created.Username)
+	}
+	if created.Expire != 1700000000 || created.DataLimit != 5 || created.Note != "vip" {
+		t.Errorf("expire, data_limit, note = %d, %d, %q, want the overrides", created.Expire, created.DataLimit, created.Note)
+	}
+	if len(created.Inbounds) != 1 || len(created.Inbounds["vmess"]) != 1 {
+		t.Errorf("inbounds = %v, want the overrides", created.Inbounds)
+	}
+	if len(created.Proxies) != 1 || created.Proxies["vmess"].ID != overrides.Proxies["vmess"].ID {
+		t.Errorf("proxies = %v, want the overrides", created.Proxies)
+	}
+}
+
+func TestCreateUserFromTemplateWithoutInbounds(t *testing.T) {
+	var created models.UserCreate
+	mc := newTemplatePanel(t, `{"id":7,"data_limit":0,"expire_duration":0}`,
+		`{"vmess":[{"tag":"VMess TCP","protocol":"vmess"}],"shadowsocks":[{"tag":"SS","protocol":"shadowsocks"}]}`, &created)
+
+	if _, err := mc.CreateUserFromTemplate(context.Background(), 7, "carol", models.UserCreate{}); err != nil {
+		t.Fatal(err)
+	}
+	if created.Expire != 0 {
+		t.Errorf("expire = %d, want 0 for an unlimited template", created.Expire)
+	}
+	if len(created.Inbounds) != 0 {
+		t.Errorf("inbounds = %v, want none so the panel grants all", created.Inbounds)
+	}
+	if _, ok := created.Proxies["shadowsocks"]; !ok || len(created.Proxies) != 2 {
+		t.Errorf("proxies = %v, want vmess and shadowsocks", created.Proxies)
+	}
+}
+
+func TestCreateUserFromTemplateNoInboundsAnywhere(t *testing.T) {
+	var created models.UserCreate
+	mc := newTemplatePanel(t, `{"id":7,"data_limit":0,"expire_duration":0}`, `{}`, &created)
+
+	_, err := mc.CreateUserFromTemplate(context.Background(), 7, "dave", models.UserCreate{})
+	if err == nil || !strings.Contains(err.Error(), "no inbounds") {
+		t.Fatalf("err = %v, want a no-inbounds error", err)
+	}
+	if created.Username != "" {
+		t.Errorf("user %q was created", created.Username)
+	}
+}
+
+func TestUserTemplateEndpoints(t *testing.T) {
+	template := models.UserTemplate{
+		ID:             9, // ignored on create and modify
+		Name:           "monthly",
+		DataLimit:      50 << 30,
+		ExpireDuration: 30 * 86400,
+		UsernamePrefix: "m_",
+		Inbounds:       map[string][]string{"vless": {"VLESS TCP"}},
+	}
+	const body = `{"name":"monthly","data_limit":53687091200,"expire_duration":2592000,"username_prefix":"m_","inbounds":{"vless":["VLESS TCP"]}}`
+	testEndpoints(t, []endpointCase{
+		{
+			name: "ListUserTemplates",
+			call: func(ctx context.Context, mc *MarzbanClient) error {
+				_, err := mc.ListUserTemplates(ctx, models.ListUserTemplatesParams{Offset: 5, Limit: 5})
+				return err
+			},
+			method: http.MethodGet, path: "/api/user_template", query: "limit=5&offset=5",
+			response: `[]`,
+		},
+		{
+			name: "GetUserTemplate",
+			call: func(ctx context.Context, mc *MarzbanClient) error {
+				_, err := mc.GetUserTemplate(ctx, 7)
+				return err
+			},
+			method: http.MethodGet, path: "/api/user_template/7",
+		},
+		{
+			name: "CreateUserTemplate",
+			call: func(ctx context.Context, mc *MarzbanClient) error {
+				_, err := mc.CreateUserTemplate(ctx, template)
+				return err
+			},
+			method: http.MethodPost, path: "/api/user_template",
+			body: body,
+		},
+		{
+			name: "ModifyUserTemplate",
+			call: func(ctx context.Context, mc *MarzbanClient) error {
+				_, err := mc.ModifyUserTemplate(ctx, 7, template)
+				return err
+			},
+			method: http.MethodPut, path: "/api/user_template/7",
+			body: body,
+		},
+		{
+			name: "DeleteUserTemplate",
+			call: func(ctx context.Context, mc *MarzbanClient) error {
+				return mc.DeleteUserTemplate(ctx, 7)
+			},
+			method: http.MethodDelete, path: "/api/user_template/7",
+		},
+	})
+}
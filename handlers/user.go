@@ -1,429 +1,167 @@
 package handlers
 
 import (
-	"bytes"
-	"encoding/json"
-	"io"
+	"context"
+	"fmt"
 	"net/http"
-	"net/url"
+	"time"
 
 	"github.com/VQIVS/marzban-sdk/internal/client"
-	"github.com/VQIVS/marzban-sdk/internal/models"
-	"github.com/VQIVS/marzban-sdk/utils"
+	"github.com/VQIVS/marzban-sdk/models"
 )
 
-func (mc *MarzbanClient) CreateUser(user models.User) (*models.User, error) {
-	reqBody := &user
-
-	reqBodyBytes, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, err
-	}
-	resp, err := mc.Client.HttpClient.Post(
-		mc.Client.BaseURL+client.EndpointUser,
-		"application/json",
-		bytes.NewBuffer(reqBodyBytes),
-	)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, &models.ErrorResponse{
-			Message: "HTTP " + resp.Status,
-			Detail:  "Failed to create user, status code: " + resp.Status + ", body: " + string(responseBody),
-		}
-	}
-	var createdUser models.User
-	if err := json.Unmarshal(responseBody, &createdUser); err != nil {
-		return nil, err
-	}
-	return &createdUser, nil
+func (mc *MarzbanClient) CreateUser(ctx context.Context, user models.UserCreate) (*models.UserResponse, error) {
+	return client.Execute[*models.UserResponse](ctx, mc.Client, client.Request{
+		Method: http.MethodPost,
+		Path:   client.EndpointUser,
+		Body:   user,
+	})
 }
 
-func (mc *MarzbanClient) GetUserByUsername(username string) (*models.User, error) {
-	endpoint := client.GetUserByUsernameEndpoint(username)
-	resp, err := mc.Client.HttpClient.Get(mc.Client.BaseURL + endpoint)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, &models.ErrorResponse{
-			Message: "HTTP " + resp.Status,
-			Detail:  "Failed to get user by username, status code: " + resp.Status + ", body: " + string(responseBody),
-		}
-	}
-	var user models.User
-	if err := json.Unmarshal(responseBody, &user); err != nil {
-		return nil, err
-	}
-	return &user, nil
+func (mc *MarzbanClient) GetUserByUsername(ctx context.Context, username string) (*models.UserResponse, error) {
+	return client.Execute[*models.UserResponse](ctx, mc.Client, client.Request{
+		Method:     http.MethodGet,
+		Path:       client.EndpointUserByUsername,
+		PathParams: map[string]string{"username": username},
+	})
 }
 
-// TODO: Double function check for improvements
-func (mc *MarzbanClient) UpdateUser(user models.User) (*models.User, error) {
-
-	reqBody := &user
-
-	reqBodyBytes, err := json.Marshal(reqBody)
-	if err != nil {
-		return nil, err
-	}
-	endpoint := client.GetUserByUsernameEndpoint(user.Username)
-
-	//TODO: Use StringToURL function in utils/common.go
-	fullURL, err := url.Parse(mc.Client.BaseURL + endpoint)
-	if err != nil {
-		return nil, err
-	}
-
-	resp, err := mc.Client.HttpClient.Do(&http.Request{
-		Method: http.MethodPut,
-		URL:    fullURL,
-		Header: http.Header{
-			"Content-Type": []string{"application/json"},
-		},
-		Body: io.NopCloser(bytes.NewBuffer(reqBodyBytes)),
+func (mc *MarzbanClient) UpdateUser(ctx context.Context, username string, user models.UserModify) (*models.UserResponse, error) {
+	return client.Execute[*models.UserResponse](ctx, mc.Client, client.Request{
+		Method:     http.MethodPut,
+		Path:       client.EndpointUserByUsername,
+		PathParams: map[string]string{"username": username},
+		Body:       user,
 	})
-
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, &models.ErrorResponse{
-			Message: "HTTP " + resp.Status,
-			Detail:  "Failed to update user, status code: " + resp.Status + ", body: " + string(responseBody),
-		}
-	}
-	var updatedUser models.User
-	if err := json.Unmarshal(responseBody, &updatedUser); err != nil {
-		return nil, err
-	}
-	return &updatedUser, nil
 }
 
-func (mc *MarzbanClient) DeleteUserByUsername(username string) error {
-	endpoint := client.GetUserByUsernameEndpoint(username)
-
-	fullURL, err := utils.StringToURL(mc.Client.BaseURL + endpoint)
-	if err != nil {
-		return err
-	}
-
-	req, err := http.NewRequest(http.MethodDelete, fullURL.String(), nil)
-	if err != nil {
-		return err
-	}
-
-	resp, err := mc.Client.HttpClient.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		responseBody, _ := io.ReadAll(resp.Body)
-		return &models.ErrorResponse{
-			Message: "HTTP " + resp.Status,
-			Detail:  "Failed to delete user, status code: " + resp.Status + ", body: " + string(responseBody),
-		}
-	}
-	return nil
+func (mc *MarzbanClient) DeleteUserByUsername(ctx context.Context, username string) error {
+	return mc.Client.Execute(ctx, client.Request{
+		Method:     http.MethodDelete,
+		Path:       client.EndpointUserByUsername,
+		PathParams: map[string]string{"username": username},
+	}, nil)
 }
-func (mc *MarzbanClient) GetUserSubURL(username string) (string, error) {
-	endpoint := client.GetUserByUsernameEndpoint(username)
-	fullURL, err := utils.StringToURL(mc.Client.BaseURL + endpoint)
-	if err != nil {
-		return "", err
-	}
-	resp, err := mc.Client.HttpClient.Get(fullURL.String())
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return "", &models.ErrorResponse{
-			Message: "HTTP " + resp.Status,
-			Detail:  "Failed to get user sub URL, status code: " + resp.Status + ", body: " + string(responseBody),
-		}
-	}
 
+// GetUserSubURL returns the subscription URL of the user. It returns an error
+// matching models.ErrNoSubscriptionURL when the panel reports none.
+func (mc *MarzbanClient) GetUserSubURL(ctx context.Context, username string) (string, error) {
 	var response struct {
 		SubURL string `json:"subscription_url"`
 	}
-	if err := json.Unmarshal(responseBody, &response); err != nil {
+	if err := mc.getUserFields(ctx, username, &response); err != nil {
 		return "", err
 	}
 	if response.SubURL == "" {
-		return "", &models.ErrorResponse{
-			Message: "No subscription URL found",
-			Detail:  "The user does not have a subscription URL.",
-		}
+		return "", fmt.Errorf("%w: %q", models.ErrNoSubscriptionURL, username)
 	}
 	return response.SubURL, nil
 }
 
-func (mc *MarzbanClient) GetUserInbounds(username string) ([]string, error) {
-	endpoint := client.GetUserByUsernameEndpoint(username)
-	fullURL, err := utils.StringToURL(mc.Client.BaseURL + endpoint)
-	if err != nil {
-		return nil, err
-	}
-	resp, err := mc.Client.HttpClient.Get(fullURL.String())
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, &models.ErrorResponse{
-			Message: "HTTP " + resp.Status,
-			Detail:  "Failed to get user inbounds, status code: " + resp.Status + ", body: " + string(responseBody),
-		}
-	}
-
+func (mc *MarzbanClient) GetUserInbounds(ctx context.Context, username string) (map[string][]string, error) {
 	var response struct {
-		Inbounds []string `json:"inbounds"`
+		Inbounds map[string][]string `json:"inbounds"`
 	}
-	if err := json.Unmarshal(responseBody, &response); err != nil {
+	if err := mc.getUserFields(ctx, username, &response); err != nil {
 		return nil, err
 	}
-
 	return response.Inbounds, nil
 }
 
-func (mc *MarzbanClient) GetUserProxies(username string) ([]string, error) {
-	endpoint := client.GetUserByUsernameEndpoint(username)
-	fullURL, err := utils.StringToURL(mc.Client.BaseURL + endpoint)
-	if err != nil {
-		return nil, err
-	}
-	resp, err := mc.Client.HttpClient.Get(fullURL.String())
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, &models.ErrorResponse{
-			Message: "HTTP " + resp.Status,
-			Detail:  "Failed to get user proxies, status code: " + resp.Status + ", body: " + string(responseBody),
-		}
-	}
-
+func (mc *MarzbanClient) GetUserProxies(ctx context.Context, username string) (map[string]models.ProxySettings, error) {
 	var response struct {
-		Proxies []string `json:"proxies"`
+		Proxies map[string]models.ProxySettings `json:"proxies"`
 	}
-	if err := json.Unmarshal(responseBody, &response); err != nil {
+	if err := mc.getUserFields(ctx, username, &response); err != nil {
 		return nil, err
 	}
-
 	return response.Proxies, nil
 }
-func (mc *MarzbanClient) GetUserUsage(username string) (int64, error) {
-	endpoint := client.GetUserByUsernameEndpoint(username)
-	fullURL, err := utils.StringToURL(mc.Client.BaseURL + endpoint)
-	if err != nil {
-		return 0, err
-	}
-	resp, err := mc.Client.HttpClient.Get(fullURL.String())
-	if err != nil {
-		return 0, err
-	}
-	defer resp.Body.Close()
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return 0, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return 0, &models.ErrorResponse{
-			Message: "HTTP " + resp.Status,
-			Detail:  "Failed to get user usage, status code: " + resp.Status + ", body: " + string(responseBody),
-		}
-	}
-
-	var response struct {
-		Usage int64 `json:"usage"`
-	}
-	if err := json.Unmarshal(responseBody, &response); err != nil {
-		return 0, err
-	}
 
-	return response.Usage, nil
+// GetUserUsage returns the traffic the user consumed on each node between
+// start and end. Zero times leave the bound to the panel, which defaults to
+// the last 30 days.
+func (mc *MarzbanClient) GetUserUsage(ctx context.Context, username string, start, end time.Time) (*models.UserUsagesResponse, error) {
+	return client.Execute[*models.UserUsagesResponse](ctx, mc.Client, client.Request{
+		Method:     http.MethodGet,
+		Path:       client.EndpointUserUsage,
+		PathParams: map[string]string{"username": username},
+		Query:      dateRangeQuery(start, end),
+	})
 }
-func (mc *MarzbanClient) GetUserStatus(username string) (string, error) {
-	endpoint := client.GetUserByUsernameEndpoint(username)
-	fullURL, err := utils.StringToURL(mc.Client.BaseURL + endpoint)
-	if err != nil {
-		return "", err
-	}
-	resp, err := mc.Client.HttpClient.Get(fullURL.String())
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return "", &models.ErrorResponse{
-			Message: "HTTP " + resp.Status,
-			Detail:  "Failed to get user status, status code: " + resp.Status + ", body: " + string(responseBody),
-		}
-	}
 
+func (mc *MarzbanClient) GetUserStatus(ctx context.Context, username string) (string, error) {
 	var response struct {
 		Status string `json:"status"`
 	}
-	if err := json.Unmarshal(responseBody, &response); err != nil {
+	if err := mc.getUserFields(ctx, username, &response); err != nil {
 		return "", err
 	}
-
 	return response.Status, nil
 }
 
-func (mc *MarzbanClient) GetUserExpire(username string) (int64, error) {
-	endpoint := client.GetUserByUsernameEndpoint(username)
-	fullURL, err := utils.StringToURL(mc.Client.BaseURL + endpoint)
-	if err != nil {
-		return 0, err
-	}
-	resp, err := mc.Client.HttpClient.Get(fullURL.String())
-	if err != nil {
-		return 0, err
-	}
-	defer resp.Body.Close()
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return 0, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return 0, &models.ErrorResponse{
-			Message: "HTTP " + resp.Status,
-			Detail:  "Failed to get user expire time, status code: " + resp.Status + ", body: " + string(responseBody),
-		}
-	}
-
+func (mc *MarzbanClient) GetUserExpire(ctx context.Context, username string) (int64, error) {
 	var response struct {
 		Expire int64 `json:"expire"`
 	}
-	if err := json.Unmarshal(responseBody, &response); err != nil {
+	if err := mc.getUserFields(ctx, username, &response); err != nil {
 		return 0, err
 	}
-
 	return response.Expire, nil
 }
 
-func (mc *MarzbanClient) ResetUserUsage(username string) error {
-	endpoint := client.GetUserByUsernameEndpoint(username) + client.EndpointUserReset
-
-	fullURL, err := utils.StringToURL(mc.Client.BaseURL + endpoint)
-	if err != nil {
-		return err
-	}
-
-	req, err := http.NewRequest(http.MethodPost, fullURL.String(), nil)
-	if err != nil {
-		return err
-	}
-
-	resp, err := mc.Client.HttpClient.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		responseBody, _ := io.ReadAll(resp.Body)
-		return &models.ErrorResponse{
-			Message: "HTTP " + resp.Status,
-			Detail:  "Failed to reset user usage, status code: " + resp.Status + ", body: " + string(responseBody),
-		}
-	}
-	return nil
+func (mc *MarzbanClient) ResetUserUsage(ctx context.Context, username string) error {
+	return mc.Client.Execute(ctx, client.Request{
+		Method:     http.MethodPost,
+		Path:       client.EndpointUserReset,
+		PathParams: map[string]string{"username": username},
+	}, nil)
 }
 
-//TODO: fix user sub revokes
-
-func (mc *MarzbanClient) RevokeUserSub(username string) error {
-	endpoint := client.GetUserByUsernameEndpoint(username) + client.EndpointUserRevokeSubsription
-
-	fullURL, err := utils.StringToURL(mc.Client.BaseURL + endpoint)
-	if err != nil {
-		return err
-	}
-
-	req, err := http.NewRequest(http.MethodPost, fullURL.String(), nil)
-	if err != nil {
-		return err
-	}
-
-	resp, err := mc.Client.HttpClient.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
+func (mc *MarzbanClient) RevokeUserSub(ctx context.Context, username string) error {
+	return mc.Client.Execute(ctx, client.Request{
+		Method:     http.MethodPost,
+		Path:       client.EndpointUserRevokeSubsription,
+		PathParams: map[string]string{"username": username},
+	}, nil)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		responseBody, _ := io.ReadAll(resp.Body)
-		return &models.ErrorResponse{
-			Message: "HTTP " + resp.Status,
-			Detail:  "Failed to revoke user subscription, status code: " + resp.Status + ", body: " + string(responseBody),
-		}
-	}
-	return nil
+// ListUsers returns the users matching params and the total number of matches.
+func (mc *MarzbanClient) ListUsers(ctx context.Context, params models.ListUsersParams) (*models.UsersResponse, error) {
+	return client.Execute[*models.UsersResponse](ctx, mc.Client, client.Request{
+		Method: http.MethodGet,
+		Path:   client.EndpointUsers,
+		Query:  params.Query(),
+	})
 }
 
-func (mc *MarzbanClient) GetExpiredUsers() ([]models.User, error) {
-	fullURL, err := utils.StringToURL(mc.Client.BaseURL + client.EndpointUsersExpired)
-	if err != nil {
-		return nil, err
-	}
-	resp, err := mc.Client.HttpClient.Get(fullURL.String())
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	if resp.StatusCode != http.StatusOK {
-		return nil, &models.ErrorResponse{
-			Message: "HTTP " + resp.Status,
-			Detail:  "Failed to get expired users, status code: " + resp.Status + ", body: " + string(responseBody),
-		}
-	}
+// GetExpiredUsers returns the usernames of the expired users.
+func (mc *MarzbanClient) GetExpiredUsers(ctx context.Context) ([]string, error) {
+	return client.Execute[[]string](ctx, mc.Client, client.Request{
+		Method: http.MethodGet,
+		Path:   client.EndpointUsersExpired,
+	})
+}
 
-	var users []models.User
-	if err := json.Unmarshal(responseBody, &users); err != nil {
-		return nil, err
-	}
+// GetUsersUsage returns the traffic of all users on each node between start
+// and end. When admins are given, only users owned by those admins count.
+func (mc *MarzbanClient) GetUsersUsage(ctx context.Context, start, end time.Time, admins ...string) (*models.UsersUsagesResponse, error) {
+	query := dateRangeQuery(start, end)
+	for _, admin := range admins {
+		query.Add("admin", admin)
+	}
+	return client.Execute[*models.UsersUsagesResponse](ctx, mc.Client, client.Request{
+		Method: http.MethodGet,
+		Path:   client.EndpointUsersUsage,
+		Query:  query,
+	})
+}
 
-	return users, nil
+// getUserFields fetches a user and decodes the fields selected by out.
+func (mc *MarzbanClient) getUserFields(ctx context.Context, username string, out any) error {
+	return mc.Client.Execute(ctx, client.Request{
+		Method:     http.MethodGet,
+		Path:       client.EndpointUserByUsername,
+		PathParams: map[string]string{"username": username},
+	}, out)
 }
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/VQIVS/marzban-sdk/models"
+)
+
+func TestGetUserSubURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/user/alice":
+			w.Write([]byte(`{"username":"alice","subscription_url":"https://sub.example.com/sub/abc"}`))
+		case "/api/user/bob":
+			w.Write([]byte(`{"username":"bob","subscription_url":""}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"detail":"User not found"}`))
+		}
+	}))
+	defer srv.Close()
+	mc := NewMarzbanClient(srv.URL)
+	ctx := context.Background()
+
+	url, err := mc.GetUserSubURL(ctx, "alice")
+	if err != nil || url != "https://sub.example.com/sub/abc" {
+		t.Errorf("alice: url = %q, err = %v", url, err)
+	}
+
+	_, err = mc.GetUserSubURL(ctx, "bob")
+	if !errors.Is(err, models.ErrNoSubscriptionURL) || errors.Is(err, models.ErrNotFound) {
+		t.Errorf("bob: err = %v, want ErrNoSubscriptionURL only", err)
+	}
+
+	_, err = mc.GetUserSubURL(ctx, "carol")
+	var apiErr *models.APIError
+	if !errors.Is(err, models.ErrNotFound) || !errors.As(err, &apiErr) || apiErr.Detail != "User not found" {
+		t.Errorf("carol: err = %v, want a not found APIError", err)
+	}
+	if errors.Is(err, models.ErrNoSubscriptionURL) {
+		t.Errorf("carol: err = %v matches ErrNoSubscriptionURL", err)
+	}
+}
@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/VQIVS/marzban-sdk/models"
+)
+
+// tokenRefreshLeeway is how long before expiry a remembered token is renewed.
+const tokenRefreshLeeway = time.Minute
+
+// WithCredentials makes the client remember the admin username and password.
+// The client then logs in on first use, refreshes the token shortly before it
+// expires, and re-authenticates and retries once when a request gets a 401.
+func WithCredentials(username, password string) ClientOption {
+	return func(c *Client) {
+		c.Username = username
+		c.Password = password
+	}
+}
+
+// Token returns the current access token.
+func (c *Client) Token() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.token
+}
+
+// SetToken replaces the access token. The token's expiry is read from its
+// JWT "exp" claim when present.
+func (c *Client) SetToken(token string) {
+	expiry := tokenExpiry(token)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = token
+	c.tokenExpiry = expiry
+}
+
+// Login exchanges req for an access token and stores it on the client.
+func (c *Client) Login(ctx context.Context, req models.UserLoginReq) (*models.UserLoginResponse, error) {
+	httpReq, err := c.NewRequest(ctx, Request{
+		Method: http.MethodPost,
+		Path:   EndpointAdminToken,
+		Form:   req.Form(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The token endpoint is called directly so that it never recurses into
+	// re-authentication.
+	resp, err := c.HttpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	var loginResponse models.UserLoginResponse
+	if err := decodeResponse(resp, &loginResponse); err != nil {
+		return nil, err
+	}
+	c.SetToken(loginResponse.AccessToken)
+	return &loginResponse, nil
+}
+
+func (c *Client) hasCredentials() bool {
+	return c.Username != "" || c.Password != ""
+}
+
+// ensureToken logs in when no token is held yet or the held one is about to
+// expire.
+func (c *Client) ensureToken(ctx context.Context) error {
+	c.mu.RLock()
+	token, expiry := c.token, c.tokenExpiry
+	c.mu.RUnlock()
+	if token != "" && (expiry.IsZero() || time.Until(expiry) > tokenRefreshLeeway) {
+		return nil
+	}
+	return c.reauthenticate(ctx, token)
+}
+
+// reauthenticate logs in again with the remembered credentials unless
+// another goroutine already replaced stale while we waited.
+func (c *Client) reauthenticate(ctx context.Context, stale string) error {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+	if c.Token() != stale {
+		return nil
+	}
+	_, err := c.Login(ctx, models.UserLoginReq{
+		Username:     c.Username,
+		Password:     c.Password,
+		ClientID:     c.ClientID,
+		ClientSecret: c.ClientSecret,
+	})
+	return err
+}
+
+// tokenExpiry decodes the "exp" claim of a JWT without verifying it.
+func tokenExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.Exp, 0)
+}
@@ -0,0 +1,162 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testJWT returns an unsigned JWT whose exp claim is exp and whose payload
+// is unique per n.
+func testJWT(exp time.Time, n int64) string {
+	payload := fmt.Sprintf(`{"sub":"admin","exp":%d,"n":%d}`, exp.Unix(), n)
+	return "eyJhbGciOiJIUzI1NiJ9." + base64.RawURLEncoding.EncodeToString([]byte(payload)) + ".sig"
+}
+
+// authPanel issues a fresh hour-long token on every login and accepts only
+// the most recently issued one.
+type authPanel struct {
+	logins int64
+	mu     sync.Mutex
+	valid  string
+	// onRequest, when set, sees every authorized non-login request.
+	onRequest func(r *http.Request, body []byte)
+}
+
+func (p *authPanel) current() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.valid
+}
+
+func (p *authPanel) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == EndpointAdminToken {
+		n := atomic.AddInt64(&p.logins, 1)
+		token := testJWT(time.Now().Add(time.Hour), n)
+		p.mu.Lock()
+		p.valid = token
+		p.mu.Unlock()
+		fmt.Fprintf(w, `{"access_token":%q,"token_type":"bearer"}`, token)
+		return
+	}
+	if r.Header.Get("Authorization") != "Bearer "+p.current() {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"detail":"Could not validate credentials"}`))
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	if p.onRequest != nil {
+		p.onRequest(r, body)
+	}
+	w.Write([]byte(`{}`))
+}
+
+func newAuthPanel(t *testing.T) (*authPanel, *httptest.Server) {
+	t.Helper()
+	p := &authPanel{}
+	srv := httptest.NewServer(p)
+	t.Cleanup(srv.Close)
+	return p, srv
+}
+
+func TestConcurrentUnauthorizedShareOneLogin(t *testing.T) {
+	panel, srv := newAuthPanel(t)
+	c := NewClient(srv.URL, WithCredentials("admin", "secret"), WithToken("revoked-token"))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- c.Execute(context.Background(), Request{Method: http.MethodGet, Path: EndpointUsers}, nil)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Execute: %v", err)
+		}
+	}
+	if got := atomic.LoadInt64(&panel.logins); got != 1 {
+		t.Fatalf("logins = %d, want 1 shared by all requests", got)
+	}
+	if c.Token() != panel.current() {
+		t.Fatalf("client token is not the one issued by the panel")
+	}
+}
+
+func TestTokenRenewedBeforeExpiry(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		expiresIn  time.Duration
+		wantLogins int64
+	}{
+		{"inside leeway", tokenRefreshLeeway / 2, 1},
+		{"outside leeway", 2 * time.Hour, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			panel, srv := newAuthPanel(t)
+			held := testJWT(time.Now().Add(tc.expiresIn), 0)
+			panel.valid = held // the held token is still accepted
+			c := NewClient(srv.URL, WithCredentials("admin", "secret"), WithToken(held))
+
+			if err := c.Execute(context.Background(), Request{Method: http.MethodGet, Path: EndpointUsers}, nil); err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			if got := atomic.LoadInt64(&panel.logins); got != tc.wantLogins {
+				t.Fatalf("logins = %d, want %d", got, tc.wantLogins)
+			}
+			if renewed := c.Token() != held; renewed != (tc.wantLogins > 0) {
+				t.Fatalf("token renewed = %v, want %v", renewed, tc.wantLogins > 0)
+			}
+		})
+	}
+}
+
+func TestBodyReplayedAfterReauthentication(t *testing.T) {
+	panel, srv := newAuthPanel(t)
+	var got []string
+	var mu sync.Mutex
+	panel.onRequest = func(r *http.Request, body []byte) {
+		mu.Lock()
+		got = append(got, string(body))
+		mu.Unlock()
+	}
+	c := NewClient(srv.URL, WithCredentials("admin", "secret"), WithToken("revoked-token"))
+
+	err := c.Execute(context.Background(), Request{
+		Method: http.MethodPost,
+		Path:   EndpointUser,
+		Body:   map[string]string{"username": "alice"},
+	}, nil)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if len(got) != 1 || got[0] != `{"username":"alice"}` {
+		t.Fatalf("authorized request bodies = %q, want the original body once", got)
+	}
+	if atomic.LoadInt64(&panel.logins) != 1 {
+		t.Fatalf("logins = %d, want 1", panel.logins)
+	}
+}
+
+func TestTokenExpiry(t *testing.T) {
+	exp := time.Unix(1893456000, 0)
+	if got := tokenExpiry(testJWT(exp, 1)); !got.Equal(exp) {
+		t.Errorf("tokenExpiry = %v, want %v", got, exp)
+	}
+	for _, token := range []string{"", "opaque", "a.!!!.c", "a." + base64.RawURLEncoding.EncodeToString([]byte(`{}`)) + ".c"} {
+		if got := tokenExpiry(token); !got.IsZero() {
+			t.Errorf("tokenExpiry(%q) = %v, want zero", token, got)
+		}
+	}
+}
@@ -1,16 +1,25 @@
 package client
 
 import (
+	"io"
 	"net/http"
+	"sync"
 	"time"
 )
 
 type Client struct {
 	HttpClient   *http.Client
 	BaseURL      string
-	Token        string
 	ClientID     string
 	ClientSecret string
+	Username     string
+	Password     string
+	Retry        RetryPolicy
+
+	mu          sync.RWMutex // guards token and tokenExpiry
+	token       string
+	tokenExpiry time.Time
+	authMu      sync.Mutex // serializes re-authentication
 }
 
 type ClientOption func(*Client)
@@ -33,6 +42,50 @@ func NewClient(baseURL string, options ...ClientOption) *Client {
 	return client
 }
 
+// Do sends req through the underlying HTTP client, attaching the bearer
+// token when one is set. Every request to the panel must go through Do, and
+// req's context governs cancellation of the whole exchange.
+//
+// When credentials are configured with WithCredentials, Do also renews the
+// token before it expires and, on a 401, logs in again and retries once.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	if !c.hasCredentials() {
+		return c.send(req, c.Token())
+	}
+	if err := c.ensureToken(req.Context()); err != nil {
+		return nil, err
+	}
+	token := c.Token()
+	resp, err := c.send(req, token)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	if req.Body != nil && req.GetBody == nil {
+		// The body has been consumed and cannot be replayed.
+		return resp, nil
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	if err := c.reauthenticate(req.Context(), token); err != nil {
+		return nil, err
+	}
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	return c.send(retry, c.Token())
+}
+
+func (c *Client) send(req *http.Request, token string) (*http.Response, error) {
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return c.sendWithRetry(req)
+}
+
 func WithTimeout(timeout time.Duration) ClientOption {
 	return func(c *Client) {
 		c.HttpClient.Timeout = timeout
@@ -54,6 +107,6 @@ func WithHTTPClient(httpClient *http.Client) ClientOption {
 
 func WithToken(token string) ClientOption {
 	return func(c *Client) {
-		c.Token = token
+		c.SetToken(token)
 	}
 }
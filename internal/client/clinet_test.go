@@ -0,0 +1,88 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/VQIVS/marzban-sdk/handlers"
+	"github.com/VQIVS/marzban-sdk/internal/client"
+	"github.com/VQIVS/marzban-sdk/models"
+)
+
+// recordingPanel answers every request with an empty user and records the
+// Authorization header it was sent with, keyed by "METHOD path".
+type recordingPanel struct {
+	mu      sync.Mutex
+	headers map[string][]string
+}
+
+func newRecordingPanel(t *testing.T) (*recordingPanel, *httptest.Server) {
+	t.Helper()
+	p := &recordingPanel{headers: map[string][]string{}}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p.mu.Lock()
+		p.headers[r.Method+" "+r.URL.Path] = r.Header.Values("Authorization")
+		p.mu.Unlock()
+		w.Write([]byte(`{"username":"alice"}`))
+	}))
+	t.Cleanup(srv.Close)
+	return p, srv
+}
+
+func callUserEndpoints(t *testing.T, mc *handlers.MarzbanClient) {
+	t.Helper()
+	ctx := context.Background()
+	if _, err := mc.CreateUser(ctx, models.UserCreate{Username: "alice"}); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if _, err := mc.GetUserByUsername(ctx, "alice"); err != nil {
+		t.Fatalf("GetUserByUsername: %v", err)
+	}
+	if err := mc.DeleteUserByUsername(ctx, "alice"); err != nil {
+		t.Fatalf("DeleteUserByUsername: %v", err)
+	}
+	if err := mc.ResetUserUsage(ctx, "alice"); err != nil {
+		t.Fatalf("ResetUserUsage: %v", err)
+	}
+}
+
+var userEndpoints = []string{
+	"POST /api/user",
+	"GET /api/user/alice",
+	"DELETE /api/user/alice",
+	"POST /api/user/alice/reset",
+}
+
+func TestDoSendsBearerToken(t *testing.T) {
+	panel, srv := newRecordingPanel(t)
+	mc := handlers.NewMarzbanClient(srv.URL, client.WithToken("secret-token"))
+
+	callUserEndpoints(t, mc)
+
+	for _, endpoint := range userEndpoints {
+		got, ok := panel.headers[endpoint]
+		if !ok {
+			t.Errorf("%s was not called", endpoint)
+			continue
+		}
+		if len(got) != 1 || got[0] != "Bearer secret-token" {
+			t.Errorf("%s: Authorization = %q, want %q", endpoint, got, "Bearer secret-token")
+		}
+	}
+}
+
+func TestDoOmitsAuthorizationWithoutToken(t *testing.T) {
+	panel, srv := newRecordingPanel(t)
+	mc := handlers.NewMarzbanClient(srv.URL)
+
+	callUserEndpoints(t, mc)
+
+	for _, endpoint := range userEndpoints {
+		if got := panel.headers[endpoint]; len(got) != 0 {
+			t.Errorf("%s: Authorization = %q, want none", endpoint, got)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package client
 
 import (
+	"fmt"
+	"net/url"
 	"strconv"
 	"strings"
 )
@@ -63,95 +65,143 @@ const (
 	EndpointBase = "/"
 )
 
+// BuildPath fills the {placeholders} of template with params, escaping each
+// value with url.PathEscape so that it stays a single path segment. Every
+// placeholder must be given a non-empty value other than "." and "..", which
+// escaping leaves as-is and proxies would resolve to another endpoint, and
+// every param must be used; a placeholder may appear more than once.
+func BuildPath(template string, params map[string]string) (string, error) {
+	var b strings.Builder
+	used := make(map[string]bool, len(params))
+	rest := template
+	for {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			b.WriteString(rest)
+			break
+		}
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("client: unterminated placeholder in %q", template)
+		}
+		name := rest[start+1 : start+end]
+		value, ok := params[name]
+		if !ok || value == "" {
+			return "", fmt.Errorf("client: missing value for {%s} in %q", name, template)
+		}
+		if value == "." || value == ".." {
+			return "", fmt.Errorf("client: invalid value %q for {%s} in %q", value, name, template)
+		}
+		b.WriteString(rest[:start])
+		b.WriteString(url.PathEscape(value))
+		used[name] = true
+		rest = rest[start+end+1:]
+	}
+	if len(used) != len(params) {
+		return "", fmt.Errorf("client: unused path params for %q", template)
+	}
+	return b.String(), nil
+}
+
 // Helper functions for parameterized endpoints
 
+// fillPath is BuildPath for the helpers below, which cannot report errors.
+// It returns template unfilled when a value is missing, so the request fails
+// on the panel instead of hitting a different endpoint.
+func fillPath(template string, params map[string]string) string {
+	path, err := BuildPath(template, params)
+	if err != nil {
+		return template
+	}
+	return path
+}
+
 // GetAdminByUsernameEndpoint returns the endpoint for a specific admin
 func GetAdminByUsernameEndpoint(username string) string {
-	return strings.Replace(EndpointAdminByUsername, "{username}", username, 1)
+	return fillPath(EndpointAdminByUsername, map[string]string{"username": username})
 }
 
 // GetAdminUsersDisableEndpoint returns the endpoint to disable all users for an admin
 func GetAdminUsersDisableEndpoint(username string) string {
-	return strings.Replace(EndpointAdminUsersDisable, "{username}", username, 1)
+	return fillPath(EndpointAdminUsersDisable, map[string]string{"username": username})
 }
 
 // GetAdminUsersActivateEndpoint returns the endpoint to activate all users for an admin
 func GetAdminUsersActivateEndpoint(username string) string {
-	return strings.Replace(EndpointAdminUsersActivate, "{username}", username, 1)
+	return fillPath(EndpointAdminUsersActivate, map[string]string{"username": username})
 }
 
 // GetAdminUsageResetEndpoint returns the endpoint to reset admin usage
 func GetAdminUsageResetEndpoint(username string) string {
-	return strings.Replace(EndpointAdminUsageReset, "{username}", username, 1)
+	return fillPath(EndpointAdminUsageReset, map[string]string{"username": username})
 }
 
 // GetAdminUsageEndpoint returns the endpoint to get admin usage
 func GetAdminUsageEndpoint(username string) string {
-	return strings.Replace(EndpointAdminUsage, "{username}", username, 1)
+	return fillPath(EndpointAdminUsage, map[string]string{"username": username})
 }
 
 // GetNodeByIDEndpoint returns the endpoint for a specific node
 func GetNodeByIDEndpoint(nodeID int) string {
-	return strings.Replace(EndpointNodeByID, "{node_id}", strconv.Itoa(nodeID), 1)
+	return fillPath(EndpointNodeByID, map[string]string{"node_id": strconv.Itoa(nodeID)})
 }
 
 // GetNodeReconnectEndpoint returns the endpoint to reconnect a node
 func GetNodeReconnectEndpoint(nodeID int) string {
-	return strings.Replace(EndpointNodeReconnect, "{node_id}", strconv.Itoa(nodeID), 1)
+	return fillPath(EndpointNodeReconnect, map[string]string{"node_id": strconv.Itoa(nodeID)})
 }
 
 // GetSubscriptionEndpoint returns the subscription endpoint for a token
 func GetSubscriptionEndpoint(token string) string {
-	return strings.Replace(EndpointSubscription, "{token}", token, 1)
+	return fillPath(EndpointSubscription, map[string]string{"token": token})
 }
 
 // GetSubscriptionInfoEndpoint returns the subscription info endpoint for a token
 func GetSubscriptionInfoEndpoint(token string) string {
-	return strings.Replace(EndpointSubscriptionInfo, "{token}", token, 1)
+	return fillPath(EndpointSubscriptionInfo, map[string]string{"token": token})
 }
 
 // GetSubscriptionUsageEndpoint returns the subscription usage endpoint for a token
 func GetSubscriptionUsageEndpoint(token string) string {
-	return strings.Replace(EndpointSubscriptionUsage, "{token}", token, 1)
+	return fillPath(EndpointSubscriptionUsage, map[string]string{"token": token})
 }
 
 // GetSubscriptionClientTypeEndpoint returns the subscription endpoint for a specific client type
 func GetSubscriptionClientTypeEndpoint(token, clientType string) string {
-	endpoint := strings.Replace(EndpointSubscriptionClientType, "{token}", token, 1)
-	return strings.Replace(endpoint, "{client_type}", clientType, 1)
+	return fillPath(EndpointSubscriptionClientType, map[string]string{"token": token, "client_type": clientType})
 }
 
 // GetUserTemplateByIDEndpoint returns the endpoint for a specific user template
 func GetUserTemplateByIDEndpoint(templateID int) string {
-	return strings.Replace(EndpointUserTemplateByID, "{template_id}", strconv.Itoa(templateID), 1)
+	return fillPath(EndpointUserTemplateByID, map[string]string{"template_id": strconv.Itoa(templateID)})
 }
 
 // GetUserByUsernameEndpoint returns the endpoint for a specific user
 func GetUserByUsernameEndpoint(username string) string {
-	return strings.Replace(EndpointUserByUsername, "{username}", username, 1)
+	return fillPath(EndpointUserByUsername, map[string]string{"username": username})
 }
 
 // GetUserResetEndpoint returns the endpoint to reset user data usage
 func GetUserResetEndpoint(username string) string {
-	return strings.Replace(EndpointUserReset, "{username}", username, 1)
+	return fillPath(EndpointUserReset, map[string]string{"username": username})
 }
 
 // GetUserRevokeSubscriptionEndpoint returns the endpoint to revoke user subscription
 func GetUserRevokeSubscriptionEndpoint(username string) string {
-	return strings.Replace(EndpointUserRevokeSubsription, "{username}", username, 1)
+	return fillPath(EndpointUserRevokeSubsription, map[string]string{"username": username})
 }
 
 // GetUserUsageEndpoint returns the endpoint to get user usage
 func GetUserUsageEndpoint(username string) string {
-	return strings.Replace(EndpointUserUsage, "{username}", username, 1)
+	return fillPath(EndpointUserUsage, map[string]string{"username": username})
 }
 
 // GetUserActiveNextEndpoint returns the endpoint to activate next plan
 func GetUserActiveNextEndpoint(username string) string {
-	return strings.Replace(EndpointUserActiveNext, "{username}", username, 1)
+	return fillPath(EndpointUserActiveNext, map[string]string{"username": username})
 }
 
 // GetUserSetOwnerEndpoint returns the endpoint to set user owner
 func GetUserSetOwnerEndpoint(username string) string {
-	return strings.Replace(EndpointUserSetOwner, "{username}", username, 1)
+	return fillPath(EndpointUserSetOwner, map[string]string{"username": username})
 }
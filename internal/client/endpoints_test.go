@@ -0,0 +1,92 @@
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/VQIVS/marzban-sdk/internal/client"
+)
+
+func TestBuildPath(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		template string
+		params   map[string]string
+		want     string
+		wantErr  bool
+	}{
+		{"plain", "/api/user/{username}", map[string]string{"username": "alice"}, "/api/user/alice", false},
+		{"slash", "/api/user/{username}", map[string]string{"username": "a/b"}, "/api/user/a%2Fb", false},
+		{"question mark", "/api/user/{username}", map[string]string{"username": "a?b"}, "/api/user/a%3Fb", false},
+		{"hash", "/api/user/{username}", map[string]string{"username": "a#b"}, "/api/user/a%23b", false},
+		{"percent", "/api/user/{username}", map[string]string{"username": "100%"}, "/api/user/100%25", false},
+		{"space", "/api/user/{username}", map[string]string{"username": "a b"}, "/api/user/a%20b", false},
+		{"two params", "/sub/{token}/{client_type}", map[string]string{"token": "t", "client_type": "clash"}, "/sub/t/clash", false},
+		{"repeated", "/{name}/x/{name}", map[string]string{"name": "a"}, "/a/x/a", false},
+		{"no placeholders", "/api/users", nil, "/api/users", false},
+		{"missing", "/api/user/{username}", nil, "", true},
+		{"empty value", "/api/user/{username}", map[string]string{"username": ""}, "", true},
+		{"dot", "/api/user/{username}", map[string]string{"username": "."}, "", true},
+		{"dot dot", "/api/user/{username}", map[string]string{"username": ".."}, "", true},
+		{"dots in name", "/api/user/{username}", map[string]string{"username": "a..b"}, "/api/user/a..b", false},
+		{"unused", "/api/users", map[string]string{"username": "alice"}, "", true},
+		{"unterminated", "/api/user/{username", map[string]string{"username": "alice"}, "", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := client.BuildPath(tc.template, tc.params)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("path = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestEndpointHelpersEscape(t *testing.T) {
+	if got, want := client.GetUserByUsernameEndpoint("a/b c"), "/api/user/a%2Fb%20c"; got != want {
+		t.Errorf("GetUserByUsernameEndpoint = %q, want %q", got, want)
+	}
+	if got, want := client.GetSubscriptionClientTypeEndpoint("t#1", "sing-box"), "/sub/t%231/sing-box"; got != want {
+		t.Errorf("GetSubscriptionClientTypeEndpoint = %q, want %q", got, want)
+	}
+	if got, want := client.GetUserByUsernameEndpoint(".."), client.EndpointUserByUsername; got != want {
+		t.Errorf("GetUserByUsernameEndpoint(..) = %q, want %q", got, want)
+	}
+	if got, want := client.GetNodeByIDEndpoint(3), "/api/node/3"; got != want {
+		t.Errorf("GetNodeByIDEndpoint = %q, want %q", got, want)
+	}
+}
+
+func TestNewRequestURL(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		baseURL string
+		want    string
+	}{
+		{"host only", "https://panel.example.com", "https://panel.example.com/api/user/a%2Fb%3F%23%25%20c"},
+		{"trailing slash", "https://panel.example.com/", "https://panel.example.com/api/user/a%2Fb%3F%23%25%20c"},
+		{"path prefix", "https://panel.example.com/marzban", "https://panel.example.com/marzban/api/user/a%2Fb%3F%23%25%20c"},
+		{"prefix and trailing slash", "https://panel.example.com/marzban/", "https://panel.example.com/marzban/api/user/a%2Fb%3F%23%25%20c"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := client.NewClient(tc.baseURL)
+			req, err := c.NewRequest(context.Background(), client.Request{
+				Method:     http.MethodGet,
+				Path:       client.EndpointUserByUsername,
+				PathParams: map[string]string{"username": "a/b?#% c"},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := req.URL.String(); got != tc.want {
+				t.Errorf("URL = %q, want %q", got, tc.want)
+			}
+			if req.URL.RawQuery != "" || req.URL.Fragment != "" {
+				t.Errorf("query %q, fragment %q leaked from the username", req.URL.RawQuery, req.URL.Fragment)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/VQIVS/marzban-sdk/models"
+)
+
+const (
+	// maxResponseSize bounds how much of a response body is read. A larger
+	// successful response is an error rather than silently truncated JSON.
+	maxResponseSize = 32 << 20
+
+	userAgent = "marzban-sdk-go"
+)
+
+// Request describes a single call to the panel API.
+type Request struct {
+	Method     string
+	Path       string            // endpoint template, e.g. EndpointUserByUsername
+	PathParams map[string]string // values for the {placeholders} in Path
+	Query      url.Values
+	Body       any        // sent as JSON when non-nil
+	Form       url.Values // sent as a urlencoded form when non-nil
+}
+
+// Execute sends r through the client and decodes the JSON response into a T.
+func Execute[T any](ctx context.Context, c *Client, r Request) (T, error) {
+	var out T
+	err := c.Execute(ctx, r, &out)
+	return out, err
+}
+
+// Execute sends r through the client and decodes the JSON response into out.
+// A nil out discards the response body.
+func (c *Client) Execute(ctx context.Context, r Request, out any) error {
+	req, err := c.NewRequest(ctx, r)
+	if err != nil {
+		return err
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	return decodeResponse(resp, out)
+}
+
+// NewRequest builds the HTTP request for r against the client's BaseURL.
+func (c *Client) NewRequest(ctx context.Context, r Request) (*http.Request, error) {
+	path, err := BuildPath(r.Path, r.PathParams)
+	if err != nil {
+		return nil, err
+	}
+	fullURL, err := c.resolveURL(path, r.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	var body io.Reader
+	contentType := ""
+	switch {
+	case r.Body != nil:
+		reqBodyBytes, err := json.Marshal(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(reqBodyBytes)
+		contentType = "application/json"
+	case r.Form != nil:
+		body = strings.NewReader(r.Form.Encode())
+		contentType = "application/x-www-form-urlencoded"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, r.Method, fullURL, body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	return req, nil
+}
+
+// resolveURL joins the escaped path onto BaseURL, keeping any path prefix the
+// base carries, e.g. "https://host/panel/" + "/api/user" gives
+// "https://host/panel/api/user".
+func (c *Client) resolveURL(path string, query url.Values) (string, error) {
+	base, err := url.Parse(c.BaseURL)
+	if err != nil {
+		return "", err
+	}
+	u := *base
+	u.RawPath = strings.TrimRight(base.EscapedPath(), "/") + path
+	if u.Path, err = url.PathUnescape(u.RawPath); err != nil {
+		return "", err
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// decodeResponse reads resp, turning non-2xx statuses into *models.APIError.
+func decodeResponse(resp *http.Response, out any) error {
+	defer resp.Body.Close()
+
+	responseBody, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize+1))
+	if err != nil {
+		return err
+	}
+	tooLarge := len(responseBody) > maxResponseSize
+	if tooLarge {
+		responseBody = responseBody[:maxResponseSize]
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return models.NewAPIError(resp, responseBody)
+	}
+	if tooLarge {
+		return fmt.Errorf("client: response to %s %s exceeds %d MiB; request fewer items per page",
+			resp.Request.Method, resp.Request.URL.Path, maxResponseSize>>20)
+	}
+	if out == nil || len(responseBody) == 0 {
+		return nil
+	}
+	return json.Unmarshal(responseBody, out)
+}
@@ -0,0 +1,51 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/VQIVS/marzban-sdk/models"
+)
+
+// newSizedPanel answers every request with status and a body of size bytes.
+func newSizedPanel(t *testing.T, status, size int) *Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write(bytes.Repeat([]byte(" "), size-2))
+		w.Write([]byte("{}"))
+	}))
+	t.Cleanup(srv.Close)
+	return NewClient(srv.URL)
+}
+
+func TestDecodeResponseAtLimit(t *testing.T) {
+	c := newSizedPanel(t, http.StatusOK, maxResponseSize)
+	var out map[string]any
+	if err := c.Execute(context.Background(), Request{Method: http.MethodGet, Path: EndpointUsers}, &out); err != nil {
+		t.Fatalf("err = %v, want a %d byte body to decode", err, maxResponseSize)
+	}
+}
+
+func TestDecodeResponseTooLarge(t *testing.T) {
+	c := newSizedPanel(t, http.StatusOK, maxResponseSize+1)
+	var out map[string]any
+	err := c.Execute(context.Background(), Request{Method: http.MethodGet, Path: EndpointUsers}, &out)
+	if err == nil || !strings.Contains(err.Error(), "exceeds 32 MiB") || !strings.Contains(err.Error(), "GET /api/users") {
+		t.Fatalf("err = %v, want a response too large error", err)
+	}
+}
+
+func TestDecodeResponseTooLargeError(t *testing.T) {
+	c := newSizedPanel(t, http.StatusInternalServerError, maxResponseSize+1)
+	err := c.Execute(context.Background(), Request{Method: http.MethodGet, Path: EndpointUsers}, nil)
+	var apiErr *models.APIError
+	if !errors.As(err, &apiErr) || len(apiErr.Body) != maxResponseSize {
+		t.Fatalf("err = %v, want an APIError with the body truncated to the limit", err)
+	}
+}
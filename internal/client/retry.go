@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how requests that fail transiently are retried. The
+// zero value disables retries.
+type RetryPolicy struct {
+	MaxAttempts int           // total attempts, including the first
+	MinBackoff  time.Duration // base delay before the first retry
+	MaxBackoff  time.Duration // upper bound of every delay, Retry-After included
+	RetryPOST   bool          // also retry POST requests, which are not idempotent
+}
+
+// DefaultRetryPolicy retries idempotent requests up to three times in total.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	MinBackoff:  200 * time.Millisecond,
+	MaxBackoff:  5 * time.Second,
+}
+
+// WithRetryPolicy retries connection errors and 429, 502, 503 and 504
+// responses according to policy. A Retry-After header sets the delay before
+// the next attempt but is capped at policy.MaxBackoff when that is set, so a
+// proxy asking for an hour does not stall the call; bound the wait further
+// with a context deadline.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		c.Retry = policy
+	}
+}
+
+// allows reports whether req may be sent more than once.
+func (p RetryPolicy) allows(req *http.Request) bool {
+	if p.MaxAttempts <= 1 || (req.Body != nil && req.GetBody == nil) {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	case http.MethodPost:
+		return p.RetryPOST
+	}
+	return false
+}
+
+// backoff returns the delay before retry number attempt. A Retry-After
+// header on resp takes precedence over the exponential delay, up to
+// MaxBackoff.
+func (p RetryPolicy) backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			if p.MaxBackoff > 0 && d > p.MaxBackoff {
+				d = p.MaxBackoff
+			}
+			return d
+		}
+	}
+	d := p.MinBackoff << (attempt - 1)
+	if d <= 0 || (p.MaxBackoff > 0 && d > p.MaxBackoff) {
+		d = p.MaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	// Equal jitter: wait at least half the delay so retries still back off.
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(d-half)+1))
+}
+
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0), true
+	}
+	return 0, false
+}
+
+// sendWithRetry sends req, retrying it as allowed by the client's policy.
+func (c *Client) sendWithRetry(req *http.Request) (*http.Response, error) {
+	if !c.Retry.allows(req) {
+		return c.HttpClient.Do(req)
+	}
+	ctx := req.Context()
+	for attempt := 1; ; attempt++ {
+		resp, err := c.HttpClient.Do(req)
+		if attempt >= c.Retry.MaxAttempts || !retryable(resp, err) {
+			return resp, err
+		}
+		delay := c.Retry.backoff(attempt, resp)
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+
+		next := req.Clone(ctx)
+		if req.GetBody != nil {
+			if next.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+		req = next
+	}
+}
@@ -0,0 +1,215 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/VQIVS/marzban-sdk/models"
+)
+
+// newFlakyPanel fails the first failures requests with status and then
+// answers 200. It returns a counter of the requests received.
+func newFlakyPanel(t *testing.T, failures int64, status int, header http.Header) (*httptest.Server, *int64) {
+	t.Helper()
+	var hits int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt64(&hits, 1) <= failures {
+			for k, v := range header {
+				w.Header()[k] = v
+			}
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &hits
+}
+
+var fastRetries = RetryPolicy{MaxAttempts: 4, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+
+func TestRetrySucceedsAfterFailures(t *testing.T) {
+	srv, hits := newFlakyPanel(t, 3, http.StatusBadGateway, nil)
+	c := NewClient(srv.URL, WithRetryPolicy(fastRetries))
+
+	err := c.Execute(context.Background(), Request{Method: http.MethodGet, Path: EndpointUsers}, nil)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if got := atomic.LoadInt64(hits); got != 4 {
+		t.Fatalf("attempts = %d, want 4", got)
+	}
+}
+
+func TestRetryPOSTRequiresOptIn(t *testing.T) {
+	for _, tc := range []struct {
+		retryPOST bool
+		wantHits  int64
+	}{
+		{retryPOST: false, wantHits: 1},
+		{retryPOST: true, wantHits: 2},
+	} {
+		srv, hits := newFlakyPanel(t, 1, http.StatusServiceUnavailable, nil)
+		policy := fastRetries
+		policy.RetryPOST = tc.retryPOST
+		c := NewClient(srv.URL, WithRetryPolicy(policy))
+
+		err := c.Execute(context.Background(), Request{
+			Method: http.MethodPost,
+			Path:   EndpointUser,
+			Body:   map[string]string{"username": "alice"},
+		}, nil)
+		if tc.retryPOST && err != nil {
+			t.Errorf("RetryPOST=true: Execute: %v", err)
+		}
+		if !tc.retryPOST && err == nil {
+			t.Errorf("RetryPOST=false: Execute succeeded, want the 503")
+		}
+		if got := atomic.LoadInt64(hits); got != tc.wantHits {
+			t.Errorf("RetryPOST=%v: attempts = %d, want %d", tc.retryPOST, got, tc.wantHits)
+		}
+	}
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	srv, hits := newFlakyPanel(t, 1, http.StatusTooManyRequests, http.Header{"Retry-After": {"1"}})
+	policy := fastRetries
+	policy.MaxBackoff = 2 * time.Second
+	c := NewClient(srv.URL, WithRetryPolicy(policy))
+
+	begin := time.Now()
+	if err := c.Execute(context.Background(), Request{Method: http.MethodGet, Path: EndpointUsers}, nil); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if elapsed := time.Since(begin); elapsed < time.Second {
+		t.Fatalf("retried after %v, want at least the 1s Retry-After", elapsed)
+	}
+	if got := atomic.LoadInt64(hits); got != 2 {
+		t.Fatalf("attempts = %d, want 2", got)
+	}
+}
+
+func TestRetryAfterCappedByMaxBackoff(t *testing.T) {
+	srv, hits := newFlakyPanel(t, 1, http.StatusServiceUnavailable, http.Header{"Retry-After": {"3600"}})
+	c := NewClient(srv.URL, WithRetryPolicy(fastRetries))
+
+	begin := time.Now()
+	if err := c.Execute(context.Background(), Request{Method: http.MethodGet, Path: EndpointUsers}, nil); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if elapsed := time.Since(begin); elapsed > time.Second {
+		t.Fatalf("retried after %v, want the %v MaxBackoff", elapsed, fastRetries.MaxBackoff)
+	}
+	if got := atomic.LoadInt64(hits); got != 2 {
+		t.Fatalf("attempts = %d, want 2", got)
+	}
+}
+
+func TestBackoffRetryAfter(t *testing.T) {
+	resp := &http.Response{Header: http.Header{"Retry-After": {"3600"}}}
+	for _, tc := range []struct {
+		maxBackoff time.Duration
+		want       time.Duration
+	}{
+		{time.Second, time.Second},
+		{2 * time.Hour, time.Hour},
+		{0, time.Hour}, // no cap configured
+	} {
+		p := RetryPolicy{MinBackoff: time.Millisecond, MaxBackoff: tc.maxBackoff}
+		if got := p.backoff(1, resp); got != tc.want {
+			t.Errorf("MaxBackoff %v: backoff = %v, want %v", tc.maxBackoff, got, tc.want)
+		}
+	}
+}
+
+func TestRetryStopsAtMaxAttempts(t *testing.T) {
+	srv, hits := newFlakyPanel(t, 100, http.StatusBadGateway, nil)
+	policy := fastRetries
+	policy.MaxAttempts = 3
+	c := NewClient(srv.URL, WithRetryPolicy(policy))
+
+	err := c.Execute(context.Background(), Request{Method: http.MethodGet, Path: EndpointUsers}, nil)
+	var apiErr *models.APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadGateway {
+		t.Fatalf("err = %v, want a 502 APIError", err)
+	}
+	if got := atomic.LoadInt64(hits); got != 3 {
+		t.Fatalf("attempts = %d, want 3", got)
+	}
+}
+
+func TestRetryCancelledDuringBackoff(t *testing.T) {
+	srv, hits := newFlakyPanel(t, 100, http.StatusBadGateway, nil)
+	c := NewClient(srv.URL, WithRetryPolicy(RetryPolicy{MaxAttempts: 5, MinBackoff: time.Minute, MaxBackoff: time.Minute}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		for atomic.LoadInt64(hits) == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		cancel()
+	}()
+
+	begin := time.Now()
+	err := c.Execute(ctx, Request{Method: http.MethodGet, Path: EndpointUsers}, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(begin); elapsed > 5*time.Second {
+		t.Fatalf("returned after %v, want prompt abort", elapsed)
+	}
+	if got := atomic.LoadInt64(hits); got != 1 {
+		t.Fatalf("attempts = %d, want 1", got)
+	}
+}
+
+func TestBackoffJitterBounds(t *testing.T) {
+	p := RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	for _, tc := range []struct {
+		attempt int
+		full    time.Duration
+	}{
+		{1, 100 * time.Millisecond},
+		{2, 200 * time.Millisecond},
+		{3, 400 * time.Millisecond},
+		{5, time.Second}, // capped by MaxBackoff
+		{40, time.Second},
+	} {
+		for i := 0; i < 100; i++ {
+			d := p.backoff(tc.attempt, nil)
+			if d < tc.full/2 || d > tc.full {
+				t.Fatalf("backoff(%d) = %v, want within [%v, %v]", tc.attempt, d, tc.full/2, tc.full)
+			}
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	future := time.Now().Add(30 * time.Second).UTC().Format(http.TimeFormat)
+	past := time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)
+	for _, tc := range []struct {
+		value string
+		ok    bool
+		min   time.Duration
+		max   time.Duration
+	}{
+		{"", false, 0, 0},
+		{"garbage", false, 0, 0},
+		{"-1", false, 0, 0},
+		{"0", true, 0, 0},
+		{"7", true, 7 * time.Second, 7 * time.Second},
+		{future, true, 28 * time.Second, 30 * time.Second},
+		{past, true, 0, 0},
+	} {
+		d, ok := parseRetryAfter(tc.value)
+		if ok != tc.ok || d < tc.min || d > tc.max {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; want %v in [%v, %v]", tc.value, d, ok, tc.ok, tc.min, tc.max)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Package marzban is the entry point of the Marzban panel SDK. It re-exports
+// the client and its options so that consumers outside this module only need
+// to import this package and the models package.
+package marzban
+
+import (
+	"github.com/VQIVS/marzban-sdk/handlers"
+	"github.com/VQIVS/marzban-sdk/internal/client"
+	"github.com/VQIVS/marzban-sdk/models"
+)
+
+// Client is a Marzban panel API client.
+type Client = handlers.MarzbanClient
+
+// UserIterator streams users page by page; see Client.IterateUsers.
+type UserIterator = handlers.UserIterator
+
+// UserIteratorOptions configures Client.IterateUsers.
+type UserIteratorOptions = handlers.UserIteratorOptions
+
+// ClientOption configures a Client.
+type ClientOption = client.ClientOption
+
+// NewClient returns a client for the panel at baseURL.
+func NewClient(baseURL string, options ...ClientOption) *Client {
+	return handlers.NewMarzbanClient(baseURL, options...)
+}
+
+var (
+	// WithTimeout sets the timeout of the underlying HTTP client.
+	WithTimeout = client.WithTimeout
+	// WithHTTPClient replaces the underlying HTTP client.
+	WithHTTPClient = client.WithHTTPClient
+	// WithToken sets the access token sent with every request.
+	WithToken = client.WithToken
+	// WithClientID sets the OAuth2 client_id and client_secret used at login.
+	WithClientID = client.WithClientID
+	// WithCredentials makes the client log in and re-authenticate on its own.
+	WithCredentials = client.WithCredentials
+	// WithRetryPolicy retries requests that fail transiently.
+	WithRetryPolicy = client.WithRetryPolicy
+)
+
+// RetryPolicy controls how requests that fail transiently are retried.
+type RetryPolicy = client.RetryPolicy
+
+// DefaultRetryPolicy retries idempotent requests up to three times in total.
+var DefaultRetryPolicy = client.DefaultRetryPolicy
+
+// APIError is returned when the panel answers with a non-2xx status.
+type APIError = models.APIError
+
+// ValidationError is one invalid field reported by a 422 response.
+type ValidationError = models.ValidationError
+
+// Sentinel errors matched by APIError through errors.Is.
+var (
+	ErrUnauthorized = models.ErrUnauthorized
+	ErrForbidden    = models.ErrForbidden
+	ErrNotFound     = models.ErrNotFound
+	ErrConflict     = models.ErrConflict
+	ErrValidation   = models.ErrValidation
+)
+
+// ErrNoSubscriptionURL is returned by GetUserSubURL when the panel reports no
+// subscription URL for the user.
+var ErrNoSubscriptionURL = models.ErrNoSubscriptionURL
@@ -0,0 +1,139 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Sentinel errors matched by APIError through errors.Is.
+var (
+	ErrUnauthorized = errors.New("marzban: unauthorized")
+	ErrForbidden    = errors.New("marzban: forbidden")
+	ErrNotFound     = errors.New("marzban: not found")
+	ErrConflict     = errors.New("marzban: conflict")
+	ErrValidation   = errors.New("marzban: validation failed")
+)
+
+// ErrNoSubscriptionURL is returned when the panel reports no subscription URL
+// for a user, which happens when it has no subscription URL prefix set.
+var ErrNoSubscriptionURL = errors.New("marzban: user has no subscription URL")
+
+// APIError is returned when the panel answers with a non-2xx status.
+type APIError struct {
+	StatusCode int
+	Method     string
+	Path       string
+	Detail     string            // the "detail" message of the body, if any
+	Errors     []ValidationError // the validation failures, see NewAPIError
+	Body       []byte
+}
+
+// ValidationError is one entry of the "detail" list FastAPI returns with a
+// 422 response, e.g. {"loc":["body","expire"],"msg":"...","type":"..."}.
+type ValidationError struct {
+	Loc  []any  `json:"loc"`
+	Msg  string `json:"msg"`
+	Type string `json:"type"`
+}
+
+// Field returns the dotted path of the invalid field, without the leading
+// request location, e.g. "proxies.vless.flow" for
+// ["body","proxies","vless","flow"].
+func (v ValidationError) Field() string {
+	loc := v.Loc
+	if len(loc) > 1 {
+		switch loc[0] {
+		case "body", "query", "path", "header", "cookie":
+			loc = loc[1:]
+		}
+	}
+	parts := make([]string, len(loc))
+	for i, p := range loc {
+		parts[i] = fmt.Sprint(p)
+	}
+	return strings.Join(parts, ".")
+}
+
+// Error implements the error interface for ValidationError
+func (v ValidationError) Error() string {
+	if field := v.Field(); field != "" {
+		return field + ": " + v.Msg
+	}
+	return v.Msg
+}
+
+// NewAPIError builds an APIError from a failed response and its body.
+// Errors holds the "detail" list of a 422 response; a plain "detail" message
+// of a 400 or 422 response, which Marzban uses for its own validation, is
+// kept in Detail and also becomes a single ValidationError without a field.
+func NewAPIError(resp *http.Response, body []byte) *APIError {
+	e := &APIError{
+		StatusCode: resp.StatusCode,
+		Body:       body,
+	}
+	if resp.Request != nil {
+		e.Method = resp.Request.Method
+		e.Path = resp.Request.URL.Path
+	}
+	var payload struct {
+		Detail json.RawMessage `json:"detail"`
+	}
+	if json.Unmarshal(body, &payload) != nil || len(payload.Detail) == 0 {
+		return e
+	}
+	// FastAPI sends either a plain message or a list of validation errors.
+	if json.Unmarshal(payload.Detail, &e.Detail) != nil {
+		json.Unmarshal(payload.Detail, &e.Errors)
+	} else if e.Detail != "" && errors.Is(e, ErrValidation) {
+		e.Errors = []ValidationError{{Msg: e.Detail}}
+	}
+	return e
+}
+
+// FieldErrors returns the validation messages keyed by field path. A
+// message not tied to a field is keyed by "".
+func (e *APIError) FieldErrors() map[string]string {
+	fields := make(map[string]string, len(e.Errors))
+	for _, v := range e.Errors {
+		fields[v.Field()] = v.Msg
+	}
+	return fields
+}
+
+// Error implements the error interface for APIError
+func (e *APIError) Error() string {
+	msg := "marzban: " + e.Method + " " + e.Path + ": " + strconv.Itoa(e.StatusCode) + " " + http.StatusText(e.StatusCode)
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	} else if len(e.Errors) > 0 {
+		details := make([]string, len(e.Errors))
+		for i, v := range e.Errors {
+			details[i] = v.Error()
+		}
+		msg += ": " + strings.Join(details, "; ")
+	} else if len(e.Body) > 0 {
+		msg += ": " + string(e.Body)
+	}
+	return msg
+}
+
+// Is reports whether target is the sentinel error for e's status code.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrValidation:
+		return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity
+	}
+	return false
+}
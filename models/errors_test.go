@@ -0,0 +1,99 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func newTestAPIError(status int, body string) *APIError {
+	resp := &http.Response{
+		StatusCode: status,
+		Request:    &http.Request{Method: http.MethodPost, URL: &url.URL{Path: "/api/user"}},
+	}
+	return NewAPIError(resp, []byte(body))
+}
+
+func TestAPIErrorIs(t *testing.T) {
+	sentinels := []error{ErrUnauthorized, ErrForbidden, ErrNotFound, ErrConflict, ErrValidation}
+	for _, tc := range []struct {
+		status int
+		want   error
+	}{
+		{http.StatusBadRequest, ErrValidation},
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrForbidden},
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusConflict, ErrConflict},
+		{http.StatusUnprocessableEntity, ErrValidation},
+		{http.StatusInternalServerError, nil},
+	} {
+		// Wrap the error the way callers do to check errors.Is unwraps it.
+		err := fmt.Errorf("create user: %w", newTestAPIError(tc.status, `{"detail":"x"}`))
+		for _, sentinel := range sentinels {
+			if got := errors.Is(err, sentinel); got != (sentinel == tc.want) {
+				t.Errorf("status %d: errors.Is(%v) = %v", tc.status, sentinel, got)
+			}
+		}
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != tc.status {
+			t.Errorf("status %d: errors.As = %v", tc.status, apiErr)
+		}
+	}
+}
+
+func TestNewAPIErrorDetail(t *testing.T) {
+	err := newTestAPIError(http.StatusConflict, `{"detail":"User already exists"}`)
+	if err.Detail != "User already exists" || len(err.Errors) != 0 {
+		t.Errorf("detail = %q, errors = %v", err.Detail, err.Errors)
+	}
+	if want := "marzban: POST /api/user: 409 Conflict: User already exists"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestNewAPIErrorBadRequestDetail(t *testing.T) {
+	err := newTestAPIError(http.StatusBadRequest, `{"detail":"Proxy vless is disabled"}`)
+	if err.Detail != "Proxy vless is disabled" {
+		t.Errorf("detail = %q", err.Detail)
+	}
+	if len(err.Errors) != 1 || err.Errors[0].Msg != "Proxy vless is disabled" || err.Errors[0].Field() != "" {
+		t.Errorf("errors = %v, want one field-less entry", err.Errors)
+	}
+	if fields := err.FieldErrors(); fields[""] != "Proxy vless is disabled" {
+		t.Errorf("FieldErrors() = %v", fields)
+	}
+	if want := "marzban: POST /api/user: 400 Bad Request: Proxy vless is disabled"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestNewAPIErrorValidation(t *testing.T) {
+	err := newTestAPIError(http.StatusUnprocessableEntity, `{"detail":[
+		{"loc":["body","proxies","vless","flow"],"msg":"invalid flow","type":"value_error"},
+		{"loc":["body","expire"],"msg":"must be positive","type":"value_error"}
+	]}`)
+	if err.Detail != "" || len(err.Errors) != 2 {
+		t.Fatalf("detail = %q, errors = %v", err.Detail, err.Errors)
+	}
+	fields := err.FieldErrors()
+	if fields["proxies.vless.flow"] != "invalid flow" || fields["expire"] != "must be positive" {
+		t.Errorf("FieldErrors() = %v", fields)
+	}
+	want := "marzban: POST /api/user: 422 Unprocessable Entity: proxies.vless.flow: invalid flow; expire: must be positive"
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestNewAPIErrorRawBody(t *testing.T) {
+	err := newTestAPIError(http.StatusBadGateway, "upstream down")
+	if err.Detail != "" || len(err.Errors) != 0 || string(err.Body) != "upstream down" {
+		t.Errorf("err = %+v", err)
+	}
+	if want := "marzban: POST /api/user: 502 Bad Gateway: upstream down"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
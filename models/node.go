@@ -0,0 +1,66 @@
+package models
+
+// Node statuses reported by the panel.
+const (
+	NodeStatusConnected  = "connected"
+	NodeStatusConnecting = "connecting"
+	NodeStatusError      = "error"
+	NodeStatusDisabled   = "disabled"
+)
+
+// Node is a Marzban node as returned by the panel.
+type Node struct {
+	ID               int     `json:"id"`
+	Name             string  `json:"name"`
+	Address          string  `json:"address"`
+	Port             int     `json:"port"`
+	APIPort          int     `json:"api_port"`
+	UsageCoefficient float64 `json:"usage_coefficient"`
+	XrayVersion      string  `json:"xray_version"`
+	Status           string  `json:"status"`
+	Message          string  `json:"message"` // the last connection error, if any
+}
+
+// NodeCreate is the body of a request adding a node. Zero ports and usage
+// coefficient and a nil AddAsNewHost fall back to the panel defaults (62050,
+// 62051, 1 and true, which adds a host for the node to every inbound).
+type NodeCreate struct {
+	Name             string  `json:"name"`
+	Address          string  `json:"address"`
+	Port             int     `json:"port,omitempty"`
+	APIPort          int     `json:"api_port,omitempty"`
+	UsageCoefficient float64 `json:"usage_coefficient,omitempty"`
+	AddAsNewHost     *bool   `json:"add_as_new_host,omitempty"`
+}
+
+// NodeModify is the body of a request modifying a node. Zero fields are
+// left unchanged; set Status to NodeStatusDisabled to disable the node or to
+// NodeStatusConnected to enable it again.
+type NodeModify struct {
+	Name             string  `json:"name,omitempty"`
+	Address          string  `json:"address,omitempty"`
+	Port             int     `json:"port,omitempty"`
+	APIPort          int     `json:"api_port,omitempty"`
+	UsageCoefficient float64 `json:"usage_coefficient,omitempty"`
+	Status           string  `json:"status,omitempty"`
+}
+
+// NodeSettings holds what a node needs to trust the panel.
+type NodeSettings struct {
+	MinNodeVersion string `json:"min_node_version"`
+	Certificate    string `json:"certificate"` // PEM client certificate of the panel
+}
+
+// NodeUsage is the traffic that went through a node over a date range.
+// NodeID is nil for the panel's own (master) core.
+type NodeUsage struct {
+	NodeID   *int      `json:"node_id"`
+	NodeName string    `json:"node_name"`
+	Uplink   ByteCount `json:"uplink"`
+	Downlink ByteCount `json:"downlink"`
+}
+
+// NodesUsageResponse is the body returned by /api/nodes/usage.
+type NodesUsageResponse struct {
+	Usages []NodeUsage `json:"usages"`
+}
@@ -0,0 +1,174 @@
+package models
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// UserLoginReq is the OAuth2 password-grant form accepted by /api/admin/token.
+type UserLoginReq struct {
+	GrantType    string `json:"grant_type,omitempty"`
+	Username     string `json:"username"`
+	Password     string `json:"password"`
+	Scope        string `json:"scope,omitempty"`
+	ClientID     string `json:"client_id,omitempty"`
+	ClientSecret string `json:"client_secret,omitempty"`
+}
+
+// Form encodes the request as application/x-www-form-urlencoded values.
+// GrantType defaults to "password".
+func (r UserLoginReq) Form() url.Values {
+	grantType := r.GrantType
+	if grantType == "" {
+		grantType = "password"
+	}
+	return url.Values{
+		"grant_type":    {grantType},
+		"username":      {r.Username},
+		"password":      {r.Password},
+		"scope":         {r.Scope},
+		"client_id":     {r.ClientID},
+		"client_secret": {r.ClientSecret},
+	}
+}
+
+type Admin struct {
+	Username       string    `json:"username,omitempty"`
+	Sudo           bool      `json:"is_sudo"`
+	TelegramID     int64     `json:"telegram_id,omitempty"`
+	DiscordWebhook string    `json:"discord_webhook,omitempty"`
+	UsersUsage     ByteCount `json:"users_usage,omitempty"`
+	Password       string    `json:"password,omitempty"`
+}
+
+// ListAdminsParams filters the admins returned by /api/admins.
+type ListAdminsParams struct {
+	Offset   int
+	Limit    int
+	Username string
+}
+
+// Query encodes the non-zero params as query values.
+func (p ListAdminsParams) Query() url.Values {
+	q := url.Values{}
+	if p.Offset > 0 {
+		q.Set("offset", strconv.Itoa(p.Offset))
+	}
+	if p.Limit > 0 {
+		q.Set("limit", strconv.Itoa(p.Limit))
+	}
+	if p.Username != "" {
+		q.Set("username", p.Username)
+	}
+	return q
+}
+
+// User statuses, also accepted as the status filter of ListUsersParams.
+const (
+	UserStatusActive   = "active"
+	UserStatusDisabled = "disabled"
+	UserStatusLimited  = "limited"
+	UserStatusExpired  = "expired"
+	UserStatusOnHold   = "on_hold"
+)
+
+// Data limit reset strategies of a user.
+const (
+	DataLimitResetNoReset = "no_reset"
+	DataLimitResetDay     = "day"
+	DataLimitResetWeek    = "week"
+	DataLimitResetMonth   = "month"
+	DataLimitResetYear    = "year"
+)
+
+// ProxySettings are the credentials of a user for one protocol. Fields the
+// protocol does not use stay empty; leaving all of them empty lets the panel
+// generate them.
+type ProxySettings struct {
+	ID       string `json:"id,omitempty"`       // vmess, vless
+	Password string `json:"password,omitempty"` // trojan, shadowsocks
+	Flow     string `json:"flow,omitempty"`     // vless, trojan
+	Method   string `json:"method,omitempty"`   // shadowsocks
+}
+
+// NextPlan is applied to a user once the current plan runs out. A nil
+// FireOnEither leaves the panel default, true, which starts the next plan
+// when either the data limit or the expiry is reached.
+type NextPlan struct {
+	DataLimit           ByteCount `json:"data_limit"`
+	Expire              int64     `json:"expire"` // seconds the next plan lasts
+	AddRemainingTraffic bool      `json:"add_remaining_traffic"`
+	FireOnEither        *bool     `json:"fire_on_either,omitempty"`
+}
+
+// UserCreate is the body of a request creating a user. A zero Expire or
+// DataLimit means unlimited.
+type UserCreate struct {
+	Username               string                   `json:"username"`
+	Status                 string                   `json:"status,omitempty"`   // UserStatusActive or UserStatusOnHold
+	Proxies                map[string]ProxySettings `json:"proxies,omitempty"`  // protocol -> settings
+	Inbounds               map[string][]string      `json:"inbounds,omitempty"` // protocol -> inbound tags
+	Expire                 int64                    `json:"expire,omitempty"`   // UNIX timestamp
+	DataLimit              ByteCount                `json:"data_limit,omitempty"`
+	DataLimitResetStrategy string                   `json:"data_limit_reset_strategy,omitempty"`
+	Note                   string                   `json:"note,omitempty"`
+	OnHoldExpireDuration   int64                    `json:"on_hold_expire_duration,omitempty"` // seconds
+	OnHoldTimeout          *Time                    `json:"on_hold_timeout,omitempty"`
+	AutoDeleteInDays       *int                     `json:"auto_delete_in_days,omitempty"`
+	NextPlan               *NextPlan                `json:"next_plan,omitempty"`
+}
+
+// UserModify is the body of a request modifying a user. Nil and empty fields
+// are left unchanged; point Expire or DataLimit at zero to make the user
+// unlimited.
+type UserModify struct {
+	Status                 string                   `json:"status,omitempty"`
+	Proxies                map[string]ProxySettings `json:"proxies,omitempty"`
+	Inbounds               map[string][]string      `json:"inbounds,omitempty"`
+	Expire                 *int64                   `json:"expire,omitempty"`
+	DataLimit              *ByteCount               `json:"data_limit,omitempty"`
+	DataLimitResetStrategy string                   `json:"data_limit_reset_strategy,omitempty"`
+	Note                   *string                  `json:"note,omitempty"`
+	OnHoldExpireDuration   *int64                   `json:"on_hold_expire_duration,omitempty"`
+	OnHoldTimeout          *Time                    `json:"on_hold_timeout,omitempty"`
+	AutoDeleteInDays       *int                     `json:"auto_delete_in_days,omitempty"`
+	NextPlan               *NextPlan                `json:"next_plan,omitempty"`
+}
+
+// ListUsersParams filters, sorts and pages the users returned by /api/users.
+type ListUsersParams struct {
+	Offset    int
+	Limit     int
+	Usernames []string // exact usernames to return
+	Search    string   // substring of the username or note
+	Admins    []string // owner admins
+	Status    string   // one of the UserStatus constants
+	Sort      string   // e.g. "username" or "-created_at" for descending
+}
+
+// Query encodes the non-zero params as query values.
+func (p ListUsersParams) Query() url.Values {
+	q := url.Values{}
+	if p.Offset > 0 {
+		q.Set("offset", strconv.Itoa(p.Offset))
+	}
+	if p.Limit > 0 {
+		q.Set("limit", strconv.Itoa(p.Limit))
+	}
+	for _, username := range p.Usernames {
+		q.Add("username", username)
+	}
+	if p.Search != "" {
+		q.Set("search", p.Search)
+	}
+	for _, admin := range p.Admins {
+		q.Add("admin", admin)
+	}
+	if p.Status != "" {
+		q.Set("status", p.Status)
+	}
+	if p.Sort != "" {
+		q.Set("sort", p.Sort)
+	}
+	return q
+}
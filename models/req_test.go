@@ -0,0 +1,38 @@
+package models
+
+import "testing"
+
+func TestListUsersParamsQuery(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		params ListUsersParams
+		want   string
+	}{
+		{"zero", ListUsersParams{}, ""},
+		{"negative paging", ListUsersParams{Offset: -1, Limit: -5}, ""},
+		{"paging", ListUsersParams{Offset: 100, Limit: 50}, "limit=50&offset=100"},
+		{
+			"repeated usernames and admins",
+			ListUsersParams{Usernames: []string{"alice", "bob"}, Admins: []string{"r1", "r2"}},
+			"admin=r1&admin=r2&username=alice&username=bob",
+		},
+		{
+			"everything",
+			ListUsersParams{
+				Offset:    10,
+				Limit:     5,
+				Usernames: []string{"a b"},
+				Search:    "vip&co",
+				Admins:    []string{"r1"},
+				Status:    UserStatusOnHold,
+				Sort:      "-created_at",
+			},
+			"admin=r1&limit=5&offset=10&search=vip%26co&sort=-created_at&status=on_hold&username=a+b",
+		},
+		{"empty lists", ListUsersParams{Usernames: []string{}, Admins: []string{}}, ""},
+	} {
+		if got := tc.params.Query().Encode(); got != tc.want {
+			t.Errorf("%s: Query() = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
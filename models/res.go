@@ -0,0 +1,115 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// UserResponse is a user as returned by the panel.
+type UserResponse struct {
+	Username               string                   `json:"username"`
+	Status                 string                   `json:"status"`
+	Proxies                map[string]ProxySettings `json:"proxies"`  // protocol -> settings
+	Inbounds               map[string][]string      `json:"inbounds"` // protocol -> inbound tags
+	ExcludedInbounds       map[string][]string      `json:"excluded_inbounds"`
+	Expire                 int64                    `json:"expire"`     // UNIX timestamp, 0 when unlimited
+	DataLimit              ByteCount                `json:"data_limit"` // 0 when unlimited
+	DataLimitResetStrategy string                   `json:"data_limit_reset_strategy"`
+	UsedTraffic            ByteCount                `json:"used_traffic"`
+	LifetimeUsedTraffic    ByteCount                `json:"lifetime_used_traffic"`
+	Note                   string                   `json:"note"`
+	OnHoldExpireDuration   int64                    `json:"on_hold_expire_duration"` // seconds
+	OnHoldTimeout          Time                     `json:"on_hold_timeout"`
+	AutoDeleteInDays       *int                     `json:"auto_delete_in_days"`
+	NextPlan               *NextPlan                `json:"next_plan"`
+	CreatedAt              Time                     `json:"created_at"`
+	OnlineAt               Time                     `json:"online_at"`
+	SubUpdatedAt           Time                     `json:"sub_updated_at"`
+	SubLastUserAgent       string                   `json:"sub_last_user_agent"`
+	Links                  []string                 `json:"links"`
+	SubscriptionURL        string                   `json:"subscription_url"`
+	Admin                  *AdminResponse           `json:"admin"`
+}
+
+type AdminResponse struct {
+	ID             int       `json:"id"`
+	Username       string    `json:"username"`
+	IsSudo         bool      `json:"is_sudo"`
+	TelegramID     int64     `json:"telegram_id"`
+	DiscordWebhook string    `json:"discord_webhook"`
+	UsersUsage     ByteCount `json:"users_usage"`
+	CreatedAt      string    `json:"created_at"`
+	UpdatedAt      string    `json:"updated_at"`
+}
+type UserLoginResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+}
+
+// ByteCount is an amount of traffic in bytes.
+type ByteCount int64
+
+// String formats b with binary units, e.g. "1.50 GiB".
+func (b ByteCount) String() string {
+	const unit = 1024
+	if b < unit && b > -unit {
+		return strconv.FormatInt(int64(b), 10) + " B"
+	}
+	value, exp := float64(b)/unit, 0
+	for (value >= unit || value <= -unit) && exp < 5 {
+		value /= unit
+		exp++
+	}
+	return fmt.Sprintf("%.2f %ciB", value, "KMGTPE"[exp])
+}
+
+// CoreStats describes the state of the panel's Xray core.
+type CoreStats struct {
+	Version       string `json:"version"`
+	Started       bool   `json:"started"`
+	LogsWebsocket string `json:"logs_websocket"`
+}
+
+// UserUsage is the traffic a user (or a set of users) consumed on one node.
+// NodeID is nil for the panel's own (master) core.
+type UserUsage struct {
+	NodeID      *int      `json:"node_id"`
+	NodeName    string    `json:"node_name"`
+	UsedTraffic ByteCount `json:"used_traffic"`
+}
+
+// UserUsagesResponse is the per-node usage of a single user.
+type UserUsagesResponse struct {
+	Username string      `json:"username"`
+	Usages   []UserUsage `json:"usages"`
+}
+
+// Total returns the traffic used across all nodes.
+func (r UserUsagesResponse) Total() ByteCount {
+	var total ByteCount
+	for _, u := range r.Usages {
+		total += u.UsedTraffic
+	}
+	return total
+}
+
+// UsersUsagesResponse is the per-node usage summed over many users.
+type UsersUsagesResponse struct {
+	Usages []UserUsage `json:"usages"`
+}
+
+// Total returns the traffic used across all nodes.
+func (r UsersUsagesResponse) Total() ByteCount {
+	var total ByteCount
+	for _, u := range r.Usages {
+		total += u.UsedTraffic
+	}
+	return total
+}
+
+// UsersResponse is a page of users together with the number of users that
+// match the filter across all pages.
+type UsersResponse struct {
+	Users []UserResponse `json:"users"`
+	Total int            `json:"total"`
+}
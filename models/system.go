@@ -0,0 +1,58 @@
+package models
+
+// SystemStats is the panel's resource and user overview from /api/system.
+type SystemStats struct {
+	Version                string    `json:"version"`
+	MemTotal               ByteCount `json:"mem_total"`
+	MemUsed                ByteCount `json:"mem_used"`
+	CPUCores               int       `json:"cpu_cores"`
+	CPUUsage               float64   `json:"cpu_usage"` // percent
+	TotalUser              int       `json:"total_user"`
+	OnlineUsers            int       `json:"online_users"`
+	UsersActive            int       `json:"users_active"`
+	UsersOnHold            int       `json:"users_on_hold"`
+	UsersDisabled          int       `json:"users_disabled"`
+	UsersExpired           int       `json:"users_expired"`
+	UsersLimited           int       `json:"users_limited"`
+	IncomingBandwidth      ByteCount `json:"incoming_bandwidth"`
+	OutgoingBandwidth      ByteCount `json:"outgoing_bandwidth"`
+	IncomingBandwidthSpeed ByteCount `json:"incoming_bandwidth_speed"` // bytes per second
+	OutgoingBandwidthSpeed ByteCount `json:"outgoing_bandwidth_speed"` // bytes per second
+}
+
+// ProxyInbound is an Xray inbound the panel serves users on.
+type ProxyInbound struct {
+	Tag      string `json:"tag"`
+	Protocol string `json:"protocol"`
+	Network  string `json:"network"`
+	TLS      string `json:"tls"`
+	Port     Port   `json:"port"`
+}
+
+// Security values of a ProxyHost.
+const (
+	ProxyHostSecurityInboundDefault = "inbound_default"
+	ProxyHostSecurityNone           = "none"
+	ProxyHostSecurityTLS            = "tls"
+)
+
+// ProxyHost is an address users are told to connect to for an inbound, with
+// the overrides that end up in their subscription links. Empty fields fall
+// back to the inbound's own settings.
+type ProxyHost struct {
+	Remark          string `json:"remark"`
+	Address         string `json:"address"`
+	Port            *int   `json:"port,omitempty"`
+	SNI             string `json:"sni,omitempty"`
+	Host            string `json:"host,omitempty"`
+	Path            string `json:"path,omitempty"`
+	Security        string `json:"security,omitempty"`
+	ALPN            string `json:"alpn,omitempty"`
+	Fingerprint     string `json:"fingerprint,omitempty"`
+	AllowInsecure   *bool  `json:"allowinsecure,omitempty"`
+	IsDisabled      *bool  `json:"is_disabled,omitempty"`
+	MuxEnable       bool   `json:"mux_enable,omitempty"`
+	FragmentSetting string `json:"fragment_setting,omitempty"`
+	NoiseSetting    string `json:"noise_setting,omitempty"`
+	RandomUserAgent bool   `json:"random_user_agent,omitempty"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// UserTemplate is a reusable plan users can be created from. A zero
+// DataLimit or ExpireDuration means unlimited.
+type UserTemplate struct {
+	ID             int                 `json:"id,omitempty"`
+	Name           string              `json:"name,omitempty"`
+	DataLimit      ByteCount           `json:"data_limit"`
+	ExpireDuration int64               `json:"expire_duration"` // seconds
+	UsernamePrefix string              `json:"username_prefix,omitempty"`
+	UsernameSuffix string              `json:"username_suffix,omitempty"`
+	Inbounds       map[string][]string `json:"inbounds,omitempty"` // protocol -> inbound tags
+}
+
+// ListUserTemplatesParams pages the templates returned by /api/user_template.
+type ListUserTemplatesParams struct {
+	Offset int
+	Limit  int
+}
+
+// Query encodes the non-zero params as query values.
+func (p ListUserTemplatesParams) Query() url.Values {
+	q := url.Values{}
+	if p.Offset > 0 {
+		q.Set("offset", strconv.Itoa(p.Offset))
+	}
+	if p.Limit > 0 {
+		q.Set("limit", strconv.Itoa(p.Limit))
+	}
+	return q
+}
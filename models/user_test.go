@@ -0,0 +1,191 @@
+package models
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func readGolden(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// assertJSONEqual compares two JSON documents ignoring formatting and
+// member order.
+func assertJSONEqual(t *testing.T, got, want []byte) {
+	t.Helper()
+	var g, w any
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatalf("decode got: %v\n%s", err, got)
+	}
+	if err := json.Unmarshal(want, &w); err != nil {
+		t.Fatalf("decode want: %v", err)
+	}
+	if !reflect.DeepEqual(g, w) {
+		t.Fatalf("JSON mismatch\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestUserResponseGolden(t *testing.T) {
+	var user UserResponse
+	if err := json.Unmarshal(readGolden(t, "user_response.json"), &user); err != nil {
+		t.Fatal(err)
+	}
+
+	autoDelete, fireOnEither := 7, false
+	want := UserResponse{
+		Username: "alice",
+		Status:   UserStatusActive,
+		Proxies: map[string]ProxySettings{
+			"vless":       {ID: "0e3d0e6c-8b1f-4a57-9a3c-5a8d7e3b2c11", Flow: "xtls-rprx-vision"},
+			"shadowsocks": {Password: "Q2hhbmdlTWU", Method: "chacha20-ietf-poly1305"},
+		},
+		Inbounds: map[string][]string{
+			"vless":       {"VLESS TCP REALITY"},
+			"shadowsocks": {"Shadowsocks TCP"},
+		},
+		ExcludedInbounds: map[string][]string{
+			"vless":       {"VLESS WS TLS"},
+			"shadowsocks": {},
+		},
+		Expire:                 1735689600,
+		DataLimit:              50 << 30,
+		DataLimitResetStrategy: DataLimitResetMonth,
+		UsedTraffic:            1 << 30,
+		LifetimeUsedTraffic:    2 << 30,
+		Note:                   "sales bot",
+		AutoDeleteInDays:       &autoDelete,
+		NextPlan:               &NextPlan{DataLimit: 10 << 30, Expire: 2592000, AddRemainingTraffic: true, FireOnEither: &fireOnEither},
+		CreatedAt:              Time{time.Date(2024, 10, 1, 8, 0, 0, 0, time.UTC)},
+		SubUpdatedAt:           Time{time.Date(2024, 11, 2, 10, 11, 12, 345678000, time.UTC)},
+		SubLastUserAgent:       "v2rayNG/1.8.19",
+		Links: []string{
+			"vless://0e3d0e6c-8b1f-4a57-9a3c-5a8d7e3b2c11@panel.example.com:443?security=reality&type=tcp#alice",
+			"ss://Y2hhY2hhMjAtaWV0Zi1wb2x5MTMwNTpRMmhoYm1kbFRXVQ@panel.example.com:1080#alice",
+		},
+		SubscriptionURL: "/sub/YWxpY2UsMTcyNzc2OTYwMA/",
+		Admin:           &AdminResponse{Username: "reseller1", UsersUsage: 123456789},
+	}
+	if !reflect.DeepEqual(user, want) {
+		t.Fatalf("decoded user mismatch\n got: %+v\nwant: %+v", user, want)
+	}
+	if !user.OnlineAt.IsZero() || !user.OnHoldTimeout.IsZero() {
+		t.Errorf("null timestamps decoded as %v and %v, want zero", user.OnlineAt, user.OnHoldTimeout)
+	}
+
+	// Encoding and decoding again must give back the same user.
+	encoded, err := json.Marshal(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var again UserResponse
+	if err := json.Unmarshal(encoded, &again); err != nil {
+		t.Fatal(err)
+	}
+	// Marzban sends microseconds; the encoded form keeps whole seconds.
+	want.SubUpdatedAt = Time{want.SubUpdatedAt.Truncate(time.Second)}
+	if !reflect.DeepEqual(again, want) {
+		t.Fatalf("round trip mismatch\n got: %+v\nwant: %+v", again, want)
+	}
+}
+
+func TestUserCreateGolden(t *testing.T) {
+	golden := readGolden(t, "user_create.json")
+	fireOnEither := true
+	user := UserCreate{
+		Username: "bob",
+		Status:   UserStatusOnHold,
+		Proxies: map[string]ProxySettings{
+			"vless":  {Flow: "xtls-rprx-vision"},
+			"trojan": {},
+		},
+		Inbounds: map[string][]string{
+			"vless":  {"VLESS TCP REALITY"},
+			"trojan": {"TROJAN TCP TLS"},
+		},
+		DataLimit:              30 << 30,
+		DataLimitResetStrategy: DataLimitResetNoReset,
+		Note:                   "trial",
+		OnHoldExpireDuration:   2592000,
+		OnHoldTimeout:          &Time{time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		NextPlan:               &NextPlan{Expire: 604800, FireOnEither: &fireOnEither},
+	}
+
+	encoded, err := json.Marshal(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertJSONEqual(t, encoded, golden)
+
+	var decoded UserCreate
+	if err := json.Unmarshal(golden, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, user) {
+		t.Fatalf("decoded mismatch\n got: %+v\nwant: %+v", decoded, user)
+	}
+}
+
+func TestUserCreateOmitsUnsetFields(t *testing.T) {
+	encoded, err := json.Marshal(UserCreate{Username: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertJSONEqual(t, encoded, []byte(`{"username":"x"}`))
+}
+
+func TestNextPlanKeepsPanelDefault(t *testing.T) {
+	encoded, err := json.Marshal(NextPlan{Expire: 86400})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertJSONEqual(t, encoded, []byte(`{"data_limit":0,"expire":86400,"add_remaining_traffic":false}`))
+
+	fireOnEither := false
+	encoded, err = json.Marshal(NextPlan{Expire: 86400, FireOnEither: &fireOnEither})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertJSONEqual(t, encoded, []byte(`{"data_limit":0,"expire":86400,"add_remaining_traffic":false,"fire_on_either":false}`))
+}
+
+func TestUserModifyGolden(t *testing.T) {
+	golden := readGolden(t, "user_modify.json")
+	unlimited := int64(0)
+	noLimit := ByteCount(0)
+	empty := ""
+	user := UserModify{
+		Status:    UserStatusDisabled,
+		Expire:    &unlimited,
+		DataLimit: &noLimit,
+		Note:      &empty,
+	}
+
+	encoded, err := json.Marshal(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertJSONEqual(t, encoded, golden)
+
+	var decoded UserModify
+	if err := json.Unmarshal(golden, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, user) {
+		t.Fatalf("decoded mismatch\n got: %+v\nwant: %+v", decoded, user)
+	}
+
+	encoded, err = json.Marshal(UserModify{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertJSONEqual(t, encoded, []byte(`{}`))
+}
@@ -0,0 +1,325 @@
+package models
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// XrayConfig is the Xray JSON config managed through /api/core/config. Every
+// type in this file keeps members it does not model in Extra, so unknown
+// members of a config read from the panel are written back unchanged. Known
+// members are not kept verbatim: optional booleans and numbers are pointers
+// so that an explicit false or 0 survives the round trip, but empty optional
+// strings and arrays, such as "fallbacks": [], are dropped, which Xray treats
+// the same as an absent member.
+type XrayConfig struct {
+	Log       *XrayLog        `json:"log,omitempty"`
+	API       *XrayAPI        `json:"api,omitempty"`
+	DNS       json.RawMessage `json:"dns,omitempty"`
+	Routing   *XrayRouting    `json:"routing,omitempty"`
+	Policy    *XrayPolicy     `json:"policy,omitempty"`
+	Inbounds  []XrayInbound   `json:"inbounds,omitempty"`
+	Outbounds []XrayOutbound  `json:"outbounds,omitempty"`
+	Stats     *XrayStats      `json:"stats,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// InboundByTag returns the inbound with the given tag, or nil.
+func (c *XrayConfig) InboundByTag(tag string) *XrayInbound {
+	for i := range c.Inbounds {
+		if c.Inbounds[i].Tag == tag {
+			return &c.Inbounds[i]
+		}
+	}
+	return nil
+}
+
+// OutboundByTag returns the outbound with the given tag, or nil.
+func (c *XrayConfig) OutboundByTag(tag string) *XrayOutbound {
+	for i := range c.Outbounds {
+		if c.Outbounds[i].Tag == tag {
+			return &c.Outbounds[i]
+		}
+	}
+	return nil
+}
+
+type XrayLog struct {
+	Access   string `json:"access,omitempty"`
+	Error    string `json:"error,omitempty"`
+	LogLevel string `json:"loglevel,omitempty"`
+	DNSLog   *bool  `json:"dnsLog,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type XrayAPI struct {
+	Tag      string   `json:"tag,omitempty"`
+	Services []string `json:"services,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type XrayStats struct {
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type XrayInbound struct {
+	Tag            string              `json:"tag,omitempty"`
+	Listen         string              `json:"listen,omitempty"`
+	Port           Port                `json:"port,omitempty"`
+	Protocol       string              `json:"protocol"`
+	Settings       json.RawMessage     `json:"settings,omitempty"`
+	StreamSettings *XrayStreamSettings `json:"streamSettings,omitempty"`
+	Sniffing       *XraySniffing       `json:"sniffing,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// DecodeSettings decodes the protocol settings into v, e.g. a
+// *XrayVLESSInboundSettings for a "vless" inbound.
+func (i *XrayInbound) DecodeSettings(v any) error {
+	return json.Unmarshal(i.Settings, v)
+}
+
+// SetSettings replaces the protocol settings with v encoded as JSON.
+func (i *XrayInbound) SetSettings(v any) error {
+	settings, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	i.Settings = settings
+	return nil
+}
+
+type XrayOutbound struct {
+	Tag            string              `json:"tag,omitempty"`
+	Protocol       string              `json:"protocol"`
+	SendThrough    string              `json:"sendThrough,omitempty"`
+	Settings       json.RawMessage     `json:"settings,omitempty"`
+	StreamSettings *XrayStreamSettings `json:"streamSettings,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// DecodeSettings decodes the protocol settings into v.
+func (o *XrayOutbound) DecodeSettings(v any) error {
+	return json.Unmarshal(o.Settings, v)
+}
+
+// SetSettings replaces the protocol settings with v encoded as JSON.
+func (o *XrayOutbound) SetSettings(v any) error {
+	settings, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	o.Settings = settings
+	return nil
+}
+
+// XrayClient is an entry of an inbound's "clients" list. Marzban fills the
+// clients of its own inbounds at runtime, so they are usually empty here.
+type XrayClient struct {
+	ID       string `json:"id,omitempty"`
+	Password string `json:"password,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Flow     string `json:"flow,omitempty"`
+	Method   string `json:"method,omitempty"`
+	Level    *int   `json:"level,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type XrayFallback struct {
+	Name string `json:"name,omitempty"`
+	Alpn string `json:"alpn,omitempty"`
+	Path string `json:"path,omitempty"`
+	Dest Port   `json:"dest,omitempty"`
+	Xver *int   `json:"xver,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type XrayVMessInboundSettings struct {
+	Clients []XrayClient `json:"clients"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type XrayVLESSInboundSettings struct {
+	Clients    []XrayClient   `json:"clients"`
+	Decryption string         `json:"decryption,omitempty"`
+	Fallbacks  []XrayFallback `json:"fallbacks,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type XrayTrojanInboundSettings struct {
+	Clients   []XrayClient   `json:"clients"`
+	Fallbacks []XrayFallback `json:"fallbacks,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type XrayShadowsocksInboundSettings struct {
+	Clients  []XrayClient `json:"clients,omitempty"`
+	Method   string       `json:"method,omitempty"`
+	Password string       `json:"password,omitempty"`
+	Network  string       `json:"network,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type XrayStreamSettings struct {
+	Network         string               `json:"network,omitempty"`
+	Security        string               `json:"security,omitempty"`
+	TLSSettings     *XrayTLSSettings     `json:"tlsSettings,omitempty"`
+	RealitySettings *XrayRealitySettings `json:"realitySettings,omitempty"`
+	TCPSettings     json.RawMessage      `json:"tcpSettings,omitempty"`
+	WSSettings      *XrayWSSettings      `json:"wsSettings,omitempty"`
+	GRPCSettings    *XrayGRPCSettings    `json:"grpcSettings,omitempty"`
+	Sockopt         json.RawMessage      `json:"sockopt,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type XrayTLSSettings struct {
+	ServerName   string            `json:"serverName,omitempty"`
+	ALPN         []string          `json:"alpn,omitempty"`
+	Certificates []XrayCertificate `json:"certificates,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type XrayCertificate struct {
+	CertificateFile string   `json:"certificateFile,omitempty"`
+	KeyFile         string   `json:"keyFile,omitempty"`
+	Certificate     []string `json:"certificate,omitempty"`
+	Key             []string `json:"key,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type XrayRealitySettings struct {
+	Show        *bool    `json:"show,omitempty"`
+	Dest        Port     `json:"dest,omitempty"`
+	Xver        *int     `json:"xver,omitempty"`
+	ServerNames []string `json:"serverNames,omitempty"`
+	PrivateKey  string   `json:"privateKey,omitempty"`
+	ShortIDs    []string `json:"shortIds,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type XrayWSSettings struct {
+	Path    string            `json:"path,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type XrayGRPCSettings struct {
+	ServiceName string `json:"serviceName,omitempty"`
+	MultiMode   *bool  `json:"multiMode,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type XraySniffing struct {
+	Enabled      bool     `json:"enabled"`
+	DestOverride []string `json:"destOverride,omitempty"`
+	RouteOnly    *bool    `json:"routeOnly,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type XrayRouting struct {
+	DomainStrategy string            `json:"domainStrategy,omitempty"`
+	Rules          []XrayRoutingRule `json:"rules,omitempty"`
+	Balancers      json.RawMessage   `json:"balancers,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type XrayRoutingRule struct {
+	Type        string   `json:"type,omitempty"`
+	Domain      []string `json:"domain,omitempty"`
+	IP          []string `json:"ip,omitempty"`
+	Port        Port     `json:"port,omitempty"`
+	Network     string   `json:"network,omitempty"`
+	Source      []string `json:"source,omitempty"`
+	User        []string `json:"user,omitempty"`
+	InboundTag  []string `json:"inboundTag,omitempty"`
+	Protocol    []string `json:"protocol,omitempty"`
+	OutboundTag string   `json:"outboundTag,omitempty"`
+	BalancerTag string   `json:"balancerTag,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type XrayPolicy struct {
+	Levels map[string]XrayLevelPolicy `json:"levels,omitempty"`
+	System *XraySystemPolicy          `json:"system,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type XrayLevelPolicy struct {
+	Handshake         *int  `json:"handshake,omitempty"`
+	ConnIdle          *int  `json:"connIdle,omitempty"`
+	UplinkOnly        *int  `json:"uplinkOnly,omitempty"`
+	DownlinkOnly      *int  `json:"downlinkOnly,omitempty"`
+	StatsUserUplink   *bool `json:"statsUserUplink,omitempty"`
+	StatsUserDownlink *bool `json:"statsUserDownlink,omitempty"`
+	BufferSize        *int  `json:"bufferSize,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+type XraySystemPolicy struct {
+	StatsInboundUplink    *bool `json:"statsInboundUplink,omitempty"`
+	StatsInboundDownlink  *bool `json:"statsInboundDownlink,omitempty"`
+	StatsOutboundUplink   *bool `json:"statsOutboundUplink,omitempty"`
+	StatsOutboundDownlink *bool `json:"statsOutboundDownlink,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// Port is a port number or a port list/range such as "1000-2000,3000". Xray
+// accepts both JSON numbers and strings; a single port is always written
+// back as a number, so "443" comes back as 443, which Xray reads the same.
+type Port string
+
+// Int returns the port as a number when it is a single port.
+func (p Port) Int() (int, bool) {
+	n, err := strconv.Atoi(string(p))
+	return n, err == nil
+}
+
+func (p Port) MarshalJSON() ([]byte, error) {
+	if _, ok := p.Int(); ok {
+		return []byte(p), nil
+	}
+	return json.Marshal(string(p))
+}
+
+func (p *Port) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		*p = Port(s)
+		return nil
+	}
+	var n json.Number
+	if err := json.Unmarshal(data, &n); err != nil {
+		return err
+	}
+	*p = Port(n)
+	return nil
+}
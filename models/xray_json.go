@@ -0,0 +1,367 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+func (c XrayConfig) MarshalJSON() ([]byte, error) {
+	type plain XrayConfig
+	return marshalWithExtra(plain(c), c.Extra)
+}
+
+func (c *XrayConfig) UnmarshalJSON(data []byte) error {
+	type plain XrayConfig
+	extra, err := unmarshalWithExtra(data, (*plain)(c))
+	c.Extra = extra
+	return err
+}
+
+func (l XrayLog) MarshalJSON() ([]byte, error) {
+	type plain XrayLog
+	return marshalWithExtra(plain(l), l.Extra)
+}
+
+func (l *XrayLog) UnmarshalJSON(data []byte) error {
+	type plain XrayLog
+	extra, err := unmarshalWithExtra(data, (*plain)(l))
+	l.Extra = extra
+	return err
+}
+
+func (a XrayAPI) MarshalJSON() ([]byte, error) {
+	type plain XrayAPI
+	return marshalWithExtra(plain(a), a.Extra)
+}
+
+func (a *XrayAPI) UnmarshalJSON(data []byte) error {
+	type plain XrayAPI
+	extra, err := unmarshalWithExtra(data, (*plain)(a))
+	a.Extra = extra
+	return err
+}
+
+func (s XrayStats) MarshalJSON() ([]byte, error) {
+	type plain XrayStats
+	return marshalWithExtra(plain(s), s.Extra)
+}
+
+func (s *XrayStats) UnmarshalJSON(data []byte) error {
+	type plain XrayStats
+	extra, err := unmarshalWithExtra(data, (*plain)(s))
+	s.Extra = extra
+	return err
+}
+
+func (i XrayInbound) MarshalJSON() ([]byte, error) {
+	type plain XrayInbound
+	return marshalWithExtra(plain(i), i.Extra)
+}
+
+func (i *XrayInbound) UnmarshalJSON(data []byte) error {
+	type plain XrayInbound
+	extra, err := unmarshalWithExtra(data, (*plain)(i))
+	i.Extra = extra
+	return err
+}
+
+func (o XrayOutbound) MarshalJSON() ([]byte, error) {
+	type plain XrayOutbound
+	return marshalWithExtra(plain(o), o.Extra)
+}
+
+func (o *XrayOutbound) UnmarshalJSON(data []byte) error {
+	type plain XrayOutbound
+	extra, err := unmarshalWithExtra(data, (*plain)(o))
+	o.Extra = extra
+	return err
+}
+
+func (c XrayClient) MarshalJSON() ([]byte, error) {
+	type plain XrayClient
+	return marshalWithExtra(plain(c), c.Extra)
+}
+
+func (c *XrayClient) UnmarshalJSON(data []byte) error {
+	type plain XrayClient
+	extra, err := unmarshalWithExtra(data, (*plain)(c))
+	c.Extra = extra
+	return err
+}
+
+func (f XrayFallback) MarshalJSON() ([]byte, error) {
+	type plain XrayFallback
+	return marshalWithExtra(plain(f), f.Extra)
+}
+
+func (f *XrayFallback) UnmarshalJSON(data []byte) error {
+	type plain XrayFallback
+	extra, err := unmarshalWithExtra(data, (*plain)(f))
+	f.Extra = extra
+	return err
+}
+
+func (v XrayVMessInboundSettings) MarshalJSON() ([]byte, error) {
+	type plain XrayVMessInboundSettings
+	v.Clients = nonNilClients(v.Clients)
+	return marshalWithExtra(plain(v), v.Extra)
+}
+
+func (v *XrayVMessInboundSettings) UnmarshalJSON(data []byte) error {
+	type plain XrayVMessInboundSettings
+	extra, err := unmarshalWithExtra(data, (*plain)(v))
+	v.Extra = extra
+	return err
+}
+
+func (v XrayVLESSInboundSettings) MarshalJSON() ([]byte, error) {
+	type plain XrayVLESSInboundSettings
+	v.Clients = nonNilClients(v.Clients)
+	return marshalWithExtra(plain(v), v.Extra)
+}
+
+func (v *XrayVLESSInboundSettings) UnmarshalJSON(data []byte) error {
+	type plain XrayVLESSInboundSettings
+	extra, err := unmarshalWithExtra(data, (*plain)(v))
+	v.Extra = extra
+	return err
+}
+
+func (t XrayTrojanInboundSettings) MarshalJSON() ([]byte, error) {
+	type plain XrayTrojanInboundSettings
+	t.Clients = nonNilClients(t.Clients)
+	return marshalWithExtra(plain(t), t.Extra)
+}
+
+func (t *XrayTrojanInboundSettings) UnmarshalJSON(data []byte) error {
+	type plain XrayTrojanInboundSettings
+	extra, err := unmarshalWithExtra(data, (*plain)(t))
+	t.Extra = extra
+	return err
+}
+
+func (s XrayShadowsocksInboundSettings) MarshalJSON() ([]byte, error) {
+	type plain XrayShadowsocksInboundSettings
+	return marshalWithExtra(plain(s), s.Extra)
+}
+
+func (s *XrayShadowsocksInboundSettings) UnmarshalJSON(data []byte) error {
+	type plain XrayShadowsocksInboundSettings
+	extra, err := unmarshalWithExtra(data, (*plain)(s))
+	s.Extra = extra
+	return err
+}
+
+func (s XrayStreamSettings) MarshalJSON() ([]byte, error) {
+	type plain XrayStreamSettings
+	return marshalWithExtra(plain(s), s.Extra)
+}
+
+func (s *XrayStreamSettings) UnmarshalJSON(data []byte) error {
+	type plain XrayStreamSettings
+	extra, err := unmarshalWithExtra(data, (*plain)(s))
+	s.Extra = extra
+	return err
+}
+
+func (t XrayTLSSettings) MarshalJSON() ([]byte, error) {
+	type plain XrayTLSSettings
+	return marshalWithExtra(plain(t), t.Extra)
+}
+
+func (t *XrayTLSSettings) UnmarshalJSON(data []byte) error {
+	type plain XrayTLSSettings
+	extra, err := unmarshalWithExtra(data, (*plain)(t))
+	t.Extra = extra
+	return err
+}
+
+func (c XrayCertificate) MarshalJSON() ([]byte, error) {
+	type plain XrayCertificate
+	return marshalWithExtra(plain(c), c.Extra)
+}
+
+func (c *XrayCertificate) UnmarshalJSON(data []byte) error {
+	type plain XrayCertificate
+	extra, err := unmarshalWithExtra(data, (*plain)(c))
+	c.Extra = extra
+	return err
+}
+
+func (r XrayRealitySettings) MarshalJSON() ([]byte, error) {
+	type plain XrayRealitySettings
+	return marshalWithExtra(plain(r), r.Extra)
+}
+
+func (r *XrayRealitySettings) UnmarshalJSON(data []byte) error {
+	type plain XrayRealitySettings
+	extra, err := unmarshalWithExtra(data, (*plain)(r))
+	r.Extra = extra
+	return err
+}
+
+func (w XrayWSSettings) MarshalJSON() ([]byte, error) {
+	type plain XrayWSSettings
+	return marshalWithExtra(plain(w), w.Extra)
+}
+
+func (w *XrayWSSettings) UnmarshalJSON(data []byte) error {
+	type plain XrayWSSettings
+	extra, err := unmarshalWithExtra(data, (*plain)(w))
+	w.Extra = extra
+	return err
+}
+
+func (g XrayGRPCSettings) MarshalJSON() ([]byte, error) {
+	type plain XrayGRPCSettings
+	return marshalWithExtra(plain(g), g.Extra)
+}
+
+func (g *XrayGRPCSettings) UnmarshalJSON(data []byte) error {
+	type plain XrayGRPCSettings
+	extra, err := unmarshalWithExtra(data, (*plain)(g))
+	g.Extra = extra
+	return err
+}
+
+func (s XraySniffing) MarshalJSON() ([]byte, error) {
+	type plain XraySniffing
+	return marshalWithExtra(plain(s), s.Extra)
+}
+
+func (s *XraySniffing) UnmarshalJSON(data []byte) error {
+	type plain XraySniffing
+	extra, err := unmarshalWithExtra(data, (*plain)(s))
+	s.Extra = extra
+	return err
+}
+
+func (r XrayRouting) MarshalJSON() ([]byte, error) {
+	type plain XrayRouting
+	return marshalWithExtra(plain(r), r.Extra)
+}
+
+func (r *XrayRouting) UnmarshalJSON(data []byte) error {
+	type plain XrayRouting
+	extra, err := unmarshalWithExtra(data, (*plain)(r))
+	r.Extra = extra
+	return err
+}
+
+func (r XrayRoutingRule) MarshalJSON() ([]byte, error) {
+	type plain XrayRoutingRule
+	return marshalWithExtra(plain(r), r.Extra)
+}
+
+func (r *XrayRoutingRule) UnmarshalJSON(data []byte) error {
+	type plain XrayRoutingRule
+	extra, err := unmarshalWithExtra(data, (*plain)(r))
+	r.Extra = extra
+	return err
+}
+
+func (p XrayPolicy) MarshalJSON() ([]byte, error) {
+	type plain XrayPolicy
+	return marshalWithExtra(plain(p), p.Extra)
+}
+
+func (p *XrayPolicy) UnmarshalJSON(data []byte) error {
+	type plain XrayPolicy
+	extra, err := unmarshalWithExtra(data, (*plain)(p))
+	p.Extra = extra
+	return err
+}
+
+func (l XrayLevelPolicy) MarshalJSON() ([]byte, error) {
+	type plain XrayLevelPolicy
+	return marshalWithExtra(plain(l), l.Extra)
+}
+
+func (l *XrayLevelPolicy) UnmarshalJSON(data []byte) error {
+	type plain XrayLevelPolicy
+	extra, err := unmarshalWithExtra(data, (*plain)(l))
+	l.Extra = extra
+	return err
+}
+
+func (s XraySystemPolicy) MarshalJSON() ([]byte, error) {
+	type plain XraySystemPolicy
+	return marshalWithExtra(plain(s), s.Extra)
+}
+
+func (s *XraySystemPolicy) UnmarshalJSON(data []byte) error {
+	type plain XraySystemPolicy
+	extra, err := unmarshalWithExtra(data, (*plain)(s))
+	s.Extra = extra
+	return err
+}
+
+// unmarshalWithExtra decodes data into the struct pointed to by v and returns
+// the object members that match none of its fields.
+func unmarshalWithExtra(data []byte, v any) (map[string]json.RawMessage, error) {
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+	for name := range members {
+		if isKnownField(reflect.TypeOf(v).Elem(), name) {
+			delete(members, name)
+		}
+	}
+	if len(members) == 0 {
+		return nil, nil
+	}
+	return members, nil
+}
+
+// marshalWithExtra encodes the struct v and merges extra into the object.
+// Modelled fields win over extra members of the same name.
+func marshalWithExtra(v any, extra map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+	members := make(map[string]json.RawMessage, len(extra))
+	for name, value := range extra {
+		members[name] = value
+	}
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+	return json.Marshal(members)
+}
+
+// isKnownField reports whether encoding/json would decode the member name
+// into a field of t, which it matches case-insensitively.
+func isKnownField(t reflect.Type, name string) bool {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if tag == "-" {
+			continue
+		}
+		if tag == "" {
+			tag = field.Name
+		}
+		if strings.EqualFold(tag, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// nonNilClients returns clients, or an empty list when it is nil: Marzban
+// appends its users to the clients of an inbound, so they must not be null.
+func nonNilClients(clients []XrayClient) []XrayClient {
+	if clients == nil {
+		return []XrayClient{}
+	}
+	return clients
+}
@@ -0,0 +1,136 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestXrayConfigRoundTrip(t *testing.T) {
+	golden := readGolden(t, "xray_config.json")
+
+	var config XrayConfig
+	if err := json.Unmarshal(golden, &config); err != nil {
+		t.Fatal(err)
+	}
+	encoded, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertJSONEqual(t, encoded, golden)
+
+	if _, ok := config.Extra["observatory"]; !ok {
+		t.Errorf("top-level unknown member not kept in Extra: %v", config.Extra)
+	}
+	reality := config.InboundByTag("VLESS TCP REALITY").StreamSettings.RealitySettings
+	if _, ok := reality.Extra["spiderX"]; !ok {
+		t.Errorf("nested unknown member not kept in Extra: %v", reality.Extra)
+	}
+	if reality.Show == nil || *reality.Show || reality.Xver == nil || *reality.Xver != 0 {
+		t.Errorf("explicit show/xver not decoded: %v %v", reality.Show, reality.Xver)
+	}
+	if got := config.InboundByTag("VMESS WS").Port; got != "2053,2083" {
+		t.Errorf("string port = %q, want 2053,2083", got)
+	}
+	if got := config.Routing.Rules[2].Port; got != "25,465,587" {
+		t.Errorf("rule port = %q", got)
+	}
+}
+
+func TestXrayInboundSettingsRoundTrip(t *testing.T) {
+	var config XrayConfig
+	if err := json.Unmarshal(readGolden(t, "xray_config.json"), &config); err != nil {
+		t.Fatal(err)
+	}
+	inbound := config.InboundByTag("VLESS TCP REALITY")
+	original := append(json.RawMessage(nil), inbound.Settings...)
+
+	var settings XrayVLESSInboundSettings
+	if err := inbound.DecodeSettings(&settings); err != nil {
+		t.Fatal(err)
+	}
+	if settings.Decryption != "none" || len(settings.Fallbacks) != 1 {
+		t.Fatalf("decoded settings = %+v", settings)
+	}
+	if err := inbound.SetSettings(settings); err != nil {
+		t.Fatal(err)
+	}
+	assertJSONEqual(t, inbound.Settings, original)
+}
+
+func TestXrayAddInboundAndRule(t *testing.T) {
+	var config XrayConfig
+	if err := json.Unmarshal(readGolden(t, "xray_config.json"), &config); err != nil {
+		t.Fatal(err)
+	}
+
+	inbound := XrayInbound{
+		Tag:      "TROJAN TCP TLS",
+		Listen:   "0.0.0.0",
+		Port:     "2083",
+		Protocol: "trojan",
+	}
+	if err := inbound.SetSettings(XrayTrojanInboundSettings{}); err != nil {
+		t.Fatal(err)
+	}
+	assertJSONEqual(t, inbound.Settings, []byte(`{"clients":[]}`))
+	for _, settings := range []any{XrayVMessInboundSettings{}, XrayVLESSInboundSettings{Decryption: "none"}} {
+		encoded, err := json.Marshal(settings)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded struct {
+			Clients []XrayClient `json:"clients"`
+		}
+		if err := json.Unmarshal(encoded, &decoded); err != nil || decoded.Clients == nil {
+			t.Errorf("%T encoded as %s, want an empty clients list", settings, encoded)
+		}
+	}
+
+	config.Inbounds = append(config.Inbounds, inbound)
+	config.Routing.Rules = append(config.Routing.Rules, XrayRoutingRule{
+		Type:        "field",
+		InboundTag:  []string{"TROJAN TCP TLS"},
+		OutboundTag: "DIRECT",
+	})
+
+	encoded, err := json.Marshal(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var again XrayConfig
+	if err := json.Unmarshal(encoded, &again); err != nil {
+		t.Fatal(err)
+	}
+	added := again.InboundByTag("TROJAN TCP TLS")
+	if added == nil || added.Protocol != "trojan" {
+		t.Fatalf("added inbound lost: %+v", added)
+	}
+	if last := again.Routing.Rules[len(again.Routing.Rules)-1]; last.OutboundTag != "DIRECT" {
+		t.Fatalf("added rule lost: %+v", last)
+	}
+	if _, ok := again.Extra["observatory"]; !ok {
+		t.Fatalf("unknown member dropped after editing")
+	}
+}
+
+func TestPortJSON(t *testing.T) {
+	for _, tc := range []struct {
+		in, want string
+		port     Port
+	}{
+		{`443`, `443`, "443"},
+		{`"1000-2000"`, `"1000-2000"`, "1000-2000"},
+		{`"env:PORT"`, `"env:PORT"`, "env:PORT"},
+		{`"443"`, `443`, "443"}, // numeric strings are normalized to numbers
+	} {
+		var p Port
+		if err := json.Unmarshal([]byte(tc.in), &p); err != nil || p != tc.port {
+			t.Errorf("decode %s = %q, %v; want %q", tc.in, p, err, tc.port)
+			continue
+		}
+		encoded, err := json.Marshal(p)
+		if err != nil || string(encoded) != tc.want {
+			t.Errorf("encode %q = %s, %v; want %s", p, encoded, err, tc.want)
+		}
+	}
+}